@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"spotify/services/grpc"
+	"spotify/services/processor"
+	"spotify/services/spotify"
+
+	"github.com/gofiber/fiber/v2"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Timeout of the health checks of the processor
+const healthTimeout = 2 * time.Second
+
+// Component is the readiness of a dependency of the gateway
+type Component struct {
+	Ready  bool   `json:"ready"`
+	Status string `json:"status"`
+}
+
+// ConfigureHealthRoutes adds the probes, /healthz while the gateway answers and
+// /readyz while the processor, Spotify and the stream are up
+func ConfigureHealthRoutes(app *fiber.App, client *spotify.SpotifyClient, health grpc.HealthClient) {
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.Status(200).JSON(fiber.Map{"status": "ok"})
+	})
+
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), healthTimeout)
+		defer cancel()
+
+		components := map[string]Component{
+			"processor": checkHealth(ctx, health, ""),
+			"spotify":   checkHealth(ctx, health, processor.SpotifyHealth),
+			"stream":    streamStatus(client),
+		}
+
+		ready := true
+		for _, component := range components {
+			ready = ready && component.Ready
+		}
+		if !ready {
+			return c.Status(503).JSON(fiber.Map{"status": "unavailable", "components": components})
+		}
+		return c.Status(200).JSON(fiber.Map{"status": "ok", "components": components})
+	})
+}
+
+// checkHealth asks the processor for the status of the service, "" being the
+// processor itself
+func checkHealth(ctx context.Context, health grpc.HealthClient, service string) Component {
+	res, err := health.Check(ctx, &healthgrpc.HealthCheckRequest{Service: service})
+	if err != nil {
+		return Component{Ready: false, Status: "unreachable"}
+	}
+	return Component{
+		Ready:  res.Status == healthgrpc.HealthCheckResponse_SERVING,
+		Status: strings.ToLower(res.Status.String()),
+	}
+}
+
+// streamStatus is whether the gateway receives the processor events
+func streamStatus(client *spotify.SpotifyClient) Component {
+	if client.Status().Connected {
+		return Component{Ready: true, Status: "connected"}
+	}
+	return Component{Ready: false, Status: "disconnected"}
+}
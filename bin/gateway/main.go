@@ -4,29 +4,45 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"spotify/middlewares"
+	"spotify/protocols"
+	"spotify/services/bus"
+	"spotify/services/config"
 	"spotify/services/grpc"
+	"spotify/services/history"
+	"spotify/services/metrics"
+	"spotify/services/processor"
 	"spotify/services/spotify"
+	"spotify/services/tracing"
+	"spotify/services/webhook"
+	"spotify/utils"
 
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 
-	"github.com/knadh/koanf/parsers/toml"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
+	sm "github.com/zmb3/spotify/v2"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
 	log.SetFlags(log.Ltime)
 
-	k := koanf.New(".")
-	if err := k.Load(file.Provider("config.toml"), toml.Parser()); err != nil {
+	k, cfg, err := config.Load(os.Args[1:])
+	if err == nil {
+		err = config.Require(k, config.SpotifyCredentials...)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 
@@ -37,9 +53,12 @@ func main() {
 	defer logger.Sync()
 
 	fx.New(
-		fx.Supply(k, logger.Sugar()),
+		fx.Supply(k, cfg, logger.Sugar()),
+		fx.Invoke(tracing.Setup("spotify-gateway")),
+		Processor(k),
 		fx.Provide(
-			grpc.Connect,
+			config.Watch,
+			bus.New,
 			spotify.New,
 			ConfigureApp,
 		),
@@ -51,6 +70,27 @@ func main() {
 	).Run()
 }
 
+// Processor connects to the remote processor, or runs it in-process with
+// processor.mode = "embedded"
+func Processor(k *koanf.Koanf) fx.Option {
+	switch mode := k.String("processor.mode"); mode {
+	case "", "remote":
+		return fx.Provide(grpc.Connect)
+	case "embedded":
+		// every preforked process would poll Spotify and open the history
+		if k.Bool("server.prefork") {
+			log.Fatal("The embedded processor can't be used with server.prefork")
+		}
+		return fx.Options(
+			fx.Provide(grpc.Embed),
+			processor.Module,
+		)
+	default:
+		log.Fatalf("Unknown processor.mode %q", mode)
+		return nil
+	}
+}
+
 func Server(lc fx.Lifecycle, app *fiber.App, k *koanf.Koanf, client *spotify.SpotifyClient) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -63,7 +103,7 @@ func Server(lc fx.Lifecycle, app *fiber.App, k *koanf.Koanf, client *spotify.Spo
 			})
 
 			go func() {
-				if err := app.Listen(fmt.Sprintf(":%d", k.Int("server.port"))); err != nil {
+				if err := app.Listen(fmt.Sprintf("%s:%d", k.String("server.host"), k.Int("server.port"))); err != nil {
 					log.Fatal(err)
 				}
 			}()
@@ -99,21 +139,34 @@ func ConfigureMiddlewares(app *fiber.App, k *koanf.Koanf) {
 	app.Use(logger.New(logger.Config{
 		TimeZone: k.String("server.timezone"),
 	}))
+	app.Use(middlewares.Metrics())
+	app.Use(middlewares.Tracing())
 }
 
-func ConfigureRoutes(app *fiber.App, client *spotify.SpotifyClient, k *koanf.Koanf, grpc grpc.SpotifyClient) {
+func ConfigureRoutes(app *fiber.App, client *spotify.SpotifyClient, w *config.Watcher, grpc grpc.SpotifyClient, health grpc.HealthClient, bus bus.EventBus) error {
+	// the admin API keys can be reloaded
+	admin := middlewares.APIKey(func() []string {
+		return w.Koanf().Strings("admin.api_keys")
+	})
+
 	app.Get("/now-playing", func(c *fiber.Ctx) error {
 		raw, open, url := c.QueryBool("raw"), c.QueryBool("open"), ""
-		payload, err := client.GetNowPlaying(raw)
+		payload, err := client.GetNowPlaying(c.UserContext(), raw)
 		if err != nil {
 			return c.Status(500).JSON(err)
 		}
 
 		if open {
 			if raw {
-				url = payload.(*spotify.CurrentlyPlaying).Item.ExternalURLs["spotify"]
-			} else {
-				url = payload.(*spotify.Track).URL
+				if now := payload.(*spotify.CurrentlyPlaying); now != nil && now.Item != nil {
+					url = now.Item.ExternalURLs["spotify"]
+				}
+			} else if state, ok := payload.(*spotify.State); ok && state != nil {
+				url = state.Track.URL
+			}
+			// nothing playing, ads and local files can't be opened
+			if url == "" {
+				return c.SendStatus(404)
 			}
 			return c.Redirect(url, 308)
 		}
@@ -122,27 +175,259 @@ func ConfigureRoutes(app *fiber.App, client *spotify.SpotifyClient, k *koanf.Koa
 
 	app.Get("/recently-played", func(c *fiber.Ctx) error {
 		raw, open, limit, url := c.QueryBool("raw"), c.QueryBool("open"), c.QueryInt("limit"), ""
-		payload, err := client.GetLastPlayed(raw, limit)
+		before, after := c.QueryInt("before"), c.QueryInt("after")
+		if before > 0 && after > 0 {
+			return c.Status(400).SendString("Only one of before and after can be given")
+		}
+		if limit < 1 {
+			limit = spotify.DefaultRecentlyPlayed
+		} else if limit > spotify.MaxRecentlyPlayed {
+			limit = spotify.MaxRecentlyPlayed
+		}
+
+		payload, err := client.GetLastPlayed(c.UserContext(), raw, &spotify.RecentlyPlayedOptions{
+			Limit:         sm.Numeric(limit),
+			BeforeEpochMs: int64(before),
+			AfterEpochMs:  int64(after),
+		})
 		if err != nil {
 			return c.Status(500).JSON(err)
 		}
 
+		// items come newest first
+		var playedAt []time.Time
+		if raw {
+			for _, item := range payload.([]spotify.RecentlyPlayedItem) {
+				playedAt = append(playedAt, item.PlayedAt)
+				if url == "" {
+					url = item.Track.ExternalURLs["spotify"]
+				}
+			}
+		} else {
+			for _, track := range payload.([]*spotify.Track) {
+				playedAt = append(playedAt, *track.PlayedAt)
+				if url == "" {
+					url = track.URL
+				}
+			}
+		}
+
 		if open {
-			if raw {
-				url = payload.([]*spotify.RecentlyPlayedItem)[0].Track.ExternalURLs["spotify"]
-			} else {
-				url = payload.([]*spotify.Track)[0].URL
+			if url == "" {
+				return c.SendStatus(404)
 			}
 			return c.Redirect(url, 308)
 		}
-		return c.Status(200).JSON(payload)
+
+		// the pages are in the Link header and the body, empty when missing
+		next, prev := "", ""
+		if len(playedAt) > 0 {
+			link := func(cursor string, at time.Time) string {
+				return fmt.Sprintf("%s?%s=%d&limit=%d&raw=%t", c.Path(), cursor, at.UnixMilli(), limit, raw)
+			}
+			prev = link("after", playedAt[0])
+			links := []string{prev, "prev"}
+			// a short page is the last one
+			if len(playedAt) == limit {
+				next = link("before", playedAt[len(playedAt)-1])
+				links = append(links, next, "next")
+			}
+			c.Links(links...)
+		}
+		return c.Status(200).JSON(fiber.Map{"items": payload, "next": next, "prev": prev})
+	})
+
+	app.Get("/queue", func(c *fiber.Ctx) error {
+		res, err := grpc.GetQueue(c.UserContext(), &protocols.Request{})
+		if err != nil {
+			return grpcError(c, err)
+		}
+		return c.Status(200).JSON(spotify.FromProtoToQueue(res))
+	})
+
+	app.Get("/history", func(c *fiber.Ctx) error {
+		req := &protocols.HistoryRequest{
+			Cursor: c.Query("cursor"),
+			Limit:  int64(c.QueryInt("limit")),
+			Artist: c.Query("artist"),
+			Album:  c.Query("album"),
+		}
+		var err error
+		if req.From, req.To, err = queryRange(c); err != nil {
+			return c.Status(400).SendString(err.Error())
+		}
+
+		res, err := grpc.ListHistory(c.UserContext(), req)
+		if err != nil {
+			return grpcError(c, err)
+		}
+
+		plays := make([]*history.Play, len(res.Plays))
+		for i, play := range res.Plays {
+			plays[i] = history.FromProtoToPlay(play)
+		}
+		return c.Status(200).JSON(fiber.Map{"items": plays, "next": res.Cursor})
+	})
+
+	/* Stats from the recorded history */
+	stats := app.Group("/stats")
+	for path, top := range map[string]func(context.Context, *protocols.StatsRequest, ...ggrpc.CallOption) (*protocols.TopResponse, error){
+		"/top-tracks":  grpc.TopTracks,
+		"/top-artists": grpc.TopArtists,
+		"/top-albums":  grpc.TopAlbums,
+	} {
+		stats.Get(path, func(c *fiber.Ctx) error {
+			req := &protocols.StatsRequest{Limit: int64(c.QueryInt("limit"))}
+			var err error
+			if req.From, req.To, err = queryRange(c); err != nil {
+				return c.Status(400).SendString(err.Error())
+			}
+
+			res, err := top(c.UserContext(), req)
+			if err != nil {
+				return grpcError(c, err)
+			}
+
+			items := make([]*history.TopItem, len(res.Items))
+			for i, item := range res.Items {
+				items[i] = history.FromProtoToTopItem(item)
+			}
+			return c.Status(200).JSON(fiber.Map{"items": items})
+		})
+	}
+
+	stats.Get("/listening-time", func(c *fiber.Ctx) error {
+		req := &protocols.StatsRequest{Bucket: c.Query("bucket")}
+		var err error
+		if req.From, req.To, err = queryRange(c); err != nil {
+			return c.Status(400).SendString(err.Error())
+		}
+
+		res, err := grpc.ListeningTime(c.UserContext(), req)
+		if err != nil {
+			return grpcError(c, err)
+		}
+		return c.Status(200).JSON(history.FromProtoToListeningTime(res))
+	})
+
+	/* Top items and library of the user */
+	app.Get("/top/artists", func(c *fiber.Ctx) error {
+		res, err := grpc.GetTopArtists(c.UserContext(), libraryRequest(c))
+		if err != nil {
+			return grpcError(c, err)
+		}
+
+		artists := make([]spotify.Artist, len(res.Artists))
+		for i, artist := range res.Artists {
+			artists[i] = spotify.FromProtoToArtist(artist)
+		}
+		return c.Status(200).JSON(spotify.Page[spotify.Artist]{Items: artists, Total: sm.Numeric(res.Total)})
+	})
+
+	for path, list := range map[string]func(context.Context, *protocols.LibraryRequest, ...ggrpc.CallOption) (*protocols.TracksResponse, error){
+		"/top/tracks":     grpc.GetTopTracks,
+		"/library/tracks": grpc.GetSavedTracks,
+	} {
+		app.Get(path, func(c *fiber.Ctx) error {
+			res, err := list(c.UserContext(), libraryRequest(c))
+			if err != nil {
+				return grpcError(c, err)
+			}
+
+			tracks := make([]*spotify.Track, len(res.Tracks))
+			for i, track := range res.Tracks {
+				tracks[i] = spotify.FromProtoToTrack(track)
+			}
+			return c.Status(200).JSON(spotify.Page[*spotify.Track]{Items: tracks, Total: sm.Numeric(res.Total)})
+		})
+	}
+
+	app.Get("/playlists", func(c *fiber.Ctx) error {
+		res, err := grpc.GetPlaylists(c.UserContext(), libraryRequest(c))
+		if err != nil {
+			return grpcError(c, err)
+		}
+
+		playlists := make([]*spotify.Playlist, len(res.Playlists))
+		for i, playlist := range res.Playlists {
+			playlists[i] = spotify.FromProtoToPlaylist(playlist)
+		}
+		return c.Status(200).JSON(spotify.Page[*spotify.Playlist]{Items: playlists, Total: sm.Numeric(res.Total)})
+	})
+
+	/* Player commands */
+	ConfigurePlayerRoutes(app, admin, grpc)
+
+	/* Webhook deliveries, they reveal the endpoints */
+	app.Get("/webhooks", admin, func(c *fiber.Ctx) error {
+		res, err := grpc.GetWebhooks(c.UserContext(), &protocols.Request{})
+		if err != nil {
+			return grpcError(c, err)
+		}
+
+		webhooks := make([]*webhook.EndpointStatus, len(res.Webhooks))
+		for i, status := range res.Webhooks {
+			webhooks[i] = webhook.FromProtoToEndpointStatus(status)
+		}
+		return c.Status(200).JSON(fiber.Map{"items": webhooks})
 	})
 
 	/* Websocket service */
-	app.Get("/socket", middlewares.WebsocketCheck(), spotify.Socket(client, k, grpc))
+	socket, err := spotify.Socket(client, w, grpc, bus)
+	if err != nil {
+		return err
+	}
+	app.Get("/socket", middlewares.WebsocketCheck(), socket)
+	/* Probes, after the socket which follows the stream state */
+	ConfigureHealthRoutes(app, client, health)
+	/* Prometheus metrics, the embedded processor's included */
+	app.Get("/metrics", metrics.FiberHandler())
 	/* 404 */
 	app.Use(func(c *fiber.Ctx) error {
 		return c.Redirect("https://github.com/TheAmniel", 308)
 	})
+	return nil
+}
 
+// queryRange parses the "from" and "to" queries as unix milliseconds
+func queryRange(c *fiber.Ctx) (from, to *int64, err error) {
+	for name, field := range map[string]**int64{"from": &from, "to": &to} {
+		if value := c.Query(name); value != "" {
+			t, err := utils.ParseTime(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid %s: %s", name, value)
+			}
+			ms := t.UnixMilli()
+			*field = &ms
+		}
+	}
+	return from, to, nil
+}
+
+// libraryRequest reads the page of the top items or the library to request
+func libraryRequest(c *fiber.Ctx) *protocols.LibraryRequest {
+	return &protocols.LibraryRequest{
+		TimeRange: c.Query("time_range"),
+		Limit:     int64(c.QueryInt("limit")),
+		Offset:    int64(c.QueryInt("offset")),
+	}
+}
+
+// grpcStatus are the processor errors caused by the request, Spotify's state
+// or the processor being down
+var grpcStatus = map[codes.Code]int{
+	codes.InvalidArgument:    400,
+	codes.PermissionDenied:   403,
+	codes.NotFound:           404,
+	codes.FailedPrecondition: 409,
+	codes.ResourceExhausted:  429,
+	codes.Unavailable:        503,
+}
+
+// grpcError responds with the processor error, keeping the status of the known ones
+func grpcError(c *fiber.Ctx, err error) error {
+	if code, ok := grpcStatus[status.Code(err)]; ok {
+		return c.Status(code).SendString(status.Convert(err).Message())
+	}
+	return c.Status(500).JSON(err)
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+
+	"spotify/protocols"
+	"spotify/services/grpc"
+	"spotify/services/spotify"
+
+	"github.com/gofiber/fiber/v2"
+	ggrpc "google.golang.org/grpc"
+)
+
+// playerBody is the body of the player commands, every field is optional
+// except the ones the command acts on
+type playerBody struct {
+	DeviceID   string   `json:"device_id"`
+	ContextURI *string  `json:"context_uri"`
+	URIs       []string `json:"uris"`
+	PositionMs *int64   `json:"position_ms"`
+	Volume     *int64   `json:"volume"`
+	State      any      `json:"state"`
+	URI        string   `json:"uri"`
+}
+
+// ConfigurePlayerRoutes adds the player commands, only allowed through admin
+func ConfigurePlayerRoutes(app *fiber.App, admin fiber.Handler, grpc grpc.SpotifyClient) {
+	player := app.Group("/player", admin)
+	for path, command := range map[string]func(context.Context, *protocols.PlayerRequest, ...ggrpc.CallOption) (*protocols.State, error){
+		"/play":     grpc.Play,
+		"/pause":    grpc.Pause,
+		"/next":     grpc.Next,
+		"/previous": grpc.Previous,
+		"/seek":     grpc.Seek,
+		"/volume":   grpc.Volume,
+		"/shuffle":  grpc.Shuffle,
+		"/repeat":   grpc.Repeat,
+	} {
+		player.Post(path, playerCommand(command))
+	}
+	app.Post("/queue", admin, playerCommand(grpc.AddToQueue))
+}
+
+// playerCommand sends the command to the processor, responding with the resulting state
+func playerCommand(command func(context.Context, *protocols.PlayerRequest, ...ggrpc.CallOption) (*protocols.State, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body playerBody
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&body); err != nil {
+				return c.Status(400).SendString("Invalid body")
+			}
+		}
+
+		req := &protocols.PlayerRequest{
+			DeviceID:   body.DeviceID,
+			ContextURI: body.ContextURI,
+			URIs:       body.URIs,
+			Position:   body.PositionMs,
+			Volume:     body.Volume,
+			URI:        body.URI,
+		}
+		// shuffle takes a boolean state and repeat a string one
+		switch state := body.State.(type) {
+		case bool:
+			req.Shuffle = &state
+		case string:
+			req.Repeat = state
+		}
+
+		res, err := command(c.UserContext(), req)
+		if err != nil {
+			return grpcError(c, err)
+		}
+		return c.Status(200).JSON(spotify.FromProtoToState(res))
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"spotify/protocols"
+	"spotify/services/config"
+	"spotify/services/grpc"
+	"spotify/services/mqtt"
+	"spotify/services/spotify"
+
+	"go.uber.org/fx"
+)
+
+// Wait before reopening a broken stream
+const reconnectDelay = 5 * time.Second
+
+func main() {
+	log.SetFlags(log.Ltime)
+
+	k, cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fx.New(
+		fx.Supply(k, cfg),
+		fx.Provide(
+			grpc.Connect,
+			mqtt.New,
+		),
+		fx.Invoke(Bridge),
+	).Run()
+}
+
+// Bridge publishes every track the processor streams to the MQTT broker
+func Bridge(lc fx.Lifecycle, grpc grpc.SpotifyClient, publisher *mqtt.Publisher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			if err := publisher.Connect(); err != nil {
+				return err
+			}
+			log.Printf("Publishing to MQTT topics \"%s\", \"%s\" and \"%s\"", publisher.Topics.State, publisher.Topics.Track, publisher.Topics.Playing)
+			log.Println("Press CTRL-C to stop the application")
+			go listen(ctx, grpc, publisher)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			log.Println("Shutting down MQTT...")
+			cancel()
+			publisher.Close()
+			return nil
+		},
+	})
+}
+
+// listen streams the processor events until ctx is done, reopening the stream
+// whenever it breaks
+func listen(ctx context.Context, grpc grpc.SpotifyClient, publisher *mqtt.Publisher) {
+	for {
+		if err := stream(ctx, grpc, publisher); err != nil && ctx.Err() == nil {
+			log.Printf("error while reading stream: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func stream(ctx context.Context, grpc grpc.SpotifyClient, publisher *mqtt.Publisher) error {
+	id := bridgeID()
+	state, err := grpc.GetTrack(ctx, &protocols.Request{ID: id})
+	if err != nil {
+		return err
+	}
+	publisher.Publish(spotify.FromProtoToState(state))
+
+	stream, err := grpc.OnListen(ctx, &protocols.Request{ID: id})
+	if err != nil {
+		return err
+	}
+	// the broker always listens, the processor must keep polling at full rate
+	if _, err := grpc.SetDemand(ctx, &protocols.Demand{ID: id, Listeners: 1}); err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		switch res.E {
+		case "CHANGE", "DEVICE", "PLAYING":
+			publisher.Publish(&spotify.State{Track: spotify.FromProtoToTrack(res.Track), Player: spotify.FromProtoToPlayer(res.Player)})
+		case "ARTISTS":
+			// enrichments of a previous track are dropped, they come without the player
+			if current := publisher.Current(); current != nil && string(current.Track.ID) == res.Track.GetID() {
+				publisher.Publish(&spotify.State{Track: spotify.FromProtoToTrack(res.Track), Player: current.Player})
+			}
+		}
+	}
+}
+
+func bridgeID() string {
+	return fmt.Sprintf("mqtt-%d", os.Getpid())
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"spotify/services/history"
+	"spotify/services/processor"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Plays of the same track within this time of a recorded one are duplicates
+const DefaultImportTolerance = time.Minute
+
+// importHistory loads Spotify's extended streaming history exports into the history store
+func importHistory(k *koanf.Koanf, args []string) error {
+	// the same threshold as the plays recorded live, so the stats compare
+	threshold := processor.DefaultPlayThreshold
+	if k.Exists("history.threshold") {
+		threshold = k.Duration("history.threshold")
+	}
+
+	flags := flag.NewFlagSet("import-history", flag.ExitOnError)
+	minPlayed := flags.Duration("min-played", threshold, "skip the streams listened less than this, history.threshold by default")
+	tolerance := flags.Duration("tolerance", DefaultImportTolerance, "start time difference to consider two plays of a track the same")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s import-history [flags] <files...>\n", filepath.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no files to import")
+	}
+
+	path := k.String("history.path")
+	if path == "" {
+		path = processor.DefaultHistoryPath
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s (is the processor running?): %w", path, err)
+	}
+	defer store.Close()
+
+	for _, name := range flags.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		plays, err := history.ParseExport(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", name, err)
+		}
+
+		filtered := plays[:0]
+		for _, play := range plays {
+			if time.Duration(play.MsPlayed)*time.Millisecond >= *minPlayed {
+				filtered = append(filtered, play)
+			}
+		}
+
+		added, err := store.Import(filtered, *tolerance)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", name, err)
+		}
+		log.Printf("Imported %d of %d plays from \"%s\"\n", added, len(plays), name)
+	}
+	return nil
+}
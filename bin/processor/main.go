@@ -5,37 +5,55 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"os"
 
-	"github.com/knadh/koanf/parsers/toml"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 	"go.uber.org/fx"
-	"google.golang.org/grpc"
+	ggrpc "google.golang.org/grpc"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"spotify/protocols"
+	"spotify/services/config"
+	"spotify/services/grpc"
+	"spotify/services/metrics"
+	"spotify/services/processor"
 	"spotify/services/spotify"
+	"spotify/services/tracing"
 )
 
 func main() {
-	k := koanf.New(".")
-	if err := k.Load(file.Provider("config.toml"), toml.Parser()); err != nil {
+	k, cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(cfg.Args) > 0 && cfg.Args[0] == "import-history" {
+		if err := importHistory(k, cfg.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := config.Require(k, config.SpotifyCredentials...); err != nil {
 		log.Fatal(err)
 	}
 
 	fx.New(
-		fx.Supply(k),
+		fx.Supply(k, cfg),
+		fx.Invoke(tracing.Setup("spotify-processor")),
 		fx.Provide(
+			config.Watch,
 			spotify.New,
 			ConfigureApp,
 		),
-		fx.Invoke(Server),
+		processor.Module,
+		fx.Invoke(Server, metrics.Serve),
 	).Run()
 }
 
-func Server(lc fx.Lifecycle, srv *grpc.Server, k *koanf.Koanf) {
+func Server(lc fx.Lifecycle, srv *ggrpc.Server, k *koanf.Koanf) {
 	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
+		OnStart: func(_ context.Context) error {
 			list, err := net.Listen("tcp", fmt.Sprintf(":%d", k.Int("grpc.port")))
 			if err != nil {
 				return err
@@ -49,7 +67,7 @@ func Server(lc fx.Lifecycle, srv *grpc.Server, k *koanf.Koanf) {
 			}()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
+		OnStop: func(_ context.Context) error {
 			log.Println("Shutting down Grpc...")
 			srv.Stop()
 			return nil
@@ -57,9 +75,14 @@ func Server(lc fx.Lifecycle, srv *grpc.Server, k *koanf.Koanf) {
 	})
 }
 
-func ConfigureApp(client *spotify.SpotifyClient) *grpc.Server {
-	srv := grpc.NewServer()
-	protocols.RegisterSpotifyServer(srv, &server{spotify: client})
+func ConfigureApp(s protocols.SpotifyServer, health healthgrpc.HealthServer, k *koanf.Koanf) (*ggrpc.Server, error) {
+	opts, err := grpc.ServerOptions(k)
+	if err != nil {
+		return nil, err
+	}
+	srv := ggrpc.NewServer(opts...)
+	protocols.RegisterSpotifyServer(srv, s)
+	healthgrpc.RegisterHealthServer(srv, health)
 	reflection.Register(srv)
-	return srv
+	return srv, nil
 }
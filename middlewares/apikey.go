@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKey lets through the requests carrying one of the keys, either as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>". Without keys every
+// request is rejected. The keys are read on each request, so they can be
+// reloaded.
+func APIKey(keys func() []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+			key = bearer
+		}
+
+		if key != "" {
+			for _, allowed := range keys() {
+				if subtle.ConstantTimeCompare([]byte(key), []byte(allowed)) == 1 {
+					return c.Next()
+				}
+			}
+		}
+		return c.Status(401).SendString("Invalid API key.")
+	}
+}
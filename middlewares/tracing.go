@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"github.com/gofiber/contrib/otelfiber/v2"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// untraced are the routes polled by the probes and the scrapers
+var untraced = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// Tracing starts a span per REST request, continuing the trace of the caller.
+// Handlers pass c.UserContext() on so the gRPC and Spotify calls join it.
+func Tracing() fiber.Handler {
+	return otelfiber.Middleware(
+		otelfiber.WithNext(func(c *fiber.Ctx) bool {
+			return websocket.IsWebSocketUpgrade(c) || untraced[c.Path()]
+		}),
+		otelfiber.WithSpanNameFormatter(func(c *fiber.Ctx) string {
+			return c.Method() + " " + c.Route().Path
+		}),
+	)
+}
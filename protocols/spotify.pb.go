@@ -66,11 +66,15 @@ func (x *Request) GetID() string {
 }
 
 type Reponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	E             string                 `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
-	Track         *Track                 `protobuf:"bytes,3,opt,name=track,proto3,oneof" json:"track,omitempty"`
-	Progress      *int64                 `protobuf:"varint,4,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ID       string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	E        string                 `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
+	Track    *Track                 `protobuf:"bytes,3,opt,name=track,proto3,oneof" json:"track,omitempty"`
+	Progress *int64                 `protobuf:"varint,4,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	Queue    *Queue                 `protobuf:"bytes,5,opt,name=queue,proto3,oneof" json:"queue,omitempty"`
+	// Trace context of the poll the event comes from
+	Trace         map[string]string `protobuf:"bytes,6,rep,name=trace,proto3" json:"trace,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Player        *Player           `protobuf:"bytes,7,opt,name=player,proto3,oneof" json:"player,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,6 +137,79 @@ func (x *Reponse) GetProgress() int64 {
 	return 0
 }
 
+func (x *Reponse) GetQueue() *Queue {
+	if x != nil {
+		return x.Queue
+	}
+	return nil
+}
+
+func (x *Reponse) GetTrace() map[string]string {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *Reponse) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+type Demand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Listeners     int64                  `protobuf:"varint,2,opt,name=listeners,proto3" json:"listeners,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Demand) Reset() {
+	*x = Demand{}
+	mi := &file_protocols_spotify_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Demand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Demand) ProtoMessage() {}
+
+func (x *Demand) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Demand.ProtoReflect.Descriptor instead.
+func (*Demand) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Demand) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Demand) GetListeners() int64 {
+	if x != nil {
+		return x.Listeners
+	}
+	return 0
+}
+
 type Track struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
@@ -143,13 +220,24 @@ type Track struct {
 	Timestamp     *Timestamp             `protobuf:"bytes,7,opt,name=timestamp,proto3,oneof" json:"timestamp,omitempty"`
 	Title         string                 `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
 	URL           string                 `protobuf:"bytes,9,opt,name=URL,proto3" json:"URL,omitempty"`
+	Type          string                 `protobuf:"bytes,10,opt,name=type,proto3" json:"type,omitempty"`
+	Show          *Show                  `protobuf:"bytes,11,opt,name=show,proto3,oneof" json:"show,omitempty"`
+	IsLocal       bool                   `protobuf:"varint,12,opt,name=is_local,json=isLocal,proto3" json:"is_local,omitempty"`
+	Duration      int64                  `protobuf:"varint,14,opt,name=duration,proto3" json:"duration,omitempty"`
+	Explicit      bool                   `protobuf:"varint,15,opt,name=explicit,proto3" json:"explicit,omitempty"`
+	Popularity    int64                  `protobuf:"varint,16,opt,name=popularity,proto3" json:"popularity,omitempty"`
+	ISRC          string                 `protobuf:"bytes,17,opt,name=ISRC,proto3" json:"ISRC,omitempty"`
+	TrackNumber   int64                  `protobuf:"varint,18,opt,name=track_number,json=trackNumber,proto3" json:"track_number,omitempty"`
+	DiscNumber    int64                  `protobuf:"varint,19,opt,name=disc_number,json=discNumber,proto3" json:"disc_number,omitempty"`
+	PreviewURL    string                 `protobuf:"bytes,20,opt,name=previewURL,proto3" json:"previewURL,omitempty"`
+	AddedAt       *int64                 `protobuf:"varint,21,opt,name=added_at,json=addedAt,proto3,oneof" json:"added_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Track) Reset() {
 	*x = Track{}
-	mi := &file_protocols_spotify_proto_msgTypes[2]
+	mi := &file_protocols_spotify_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -161,7 +249,7 @@ func (x *Track) String() string {
 func (*Track) ProtoMessage() {}
 
 func (x *Track) ProtoReflect() protoreflect.Message {
-	mi := &file_protocols_spotify_proto_msgTypes[2]
+	mi := &file_protocols_spotify_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -174,7 +262,7 @@ func (x *Track) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Track.ProtoReflect.Descriptor instead.
 func (*Track) Descriptor() ([]byte, []int) {
-	return file_protocols_spotify_proto_rawDescGZIP(), []int{2}
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Track) GetAlbum() *Album {
@@ -233,6 +321,83 @@ func (x *Track) GetURL() string {
 	return ""
 }
 
+func (x *Track) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Track) GetShow() *Show {
+	if x != nil {
+		return x.Show
+	}
+	return nil
+}
+
+func (x *Track) GetIsLocal() bool {
+	if x != nil {
+		return x.IsLocal
+	}
+	return false
+}
+
+func (x *Track) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Track) GetExplicit() bool {
+	if x != nil {
+		return x.Explicit
+	}
+	return false
+}
+
+func (x *Track) GetPopularity() int64 {
+	if x != nil {
+		return x.Popularity
+	}
+	return 0
+}
+
+func (x *Track) GetISRC() string {
+	if x != nil {
+		return x.ISRC
+	}
+	return ""
+}
+
+func (x *Track) GetTrackNumber() int64 {
+	if x != nil {
+		return x.TrackNumber
+	}
+	return 0
+}
+
+func (x *Track) GetDiscNumber() int64 {
+	if x != nil {
+		return x.DiscNumber
+	}
+	return 0
+}
+
+func (x *Track) GetPreviewURL() string {
+	if x != nil {
+		return x.PreviewURL
+	}
+	return ""
+}
+
+func (x *Track) GetAddedAt() int64 {
+	if x != nil && x.AddedAt != nil {
+		return *x.AddedAt
+	}
+	return 0
+}
+
 type Timestamp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Progress      int64                  `protobuf:"varint,1,opt,name=progress,proto3" json:"progress,omitempty"`
@@ -243,7 +408,7 @@ type Timestamp struct {
 
 func (x *Timestamp) Reset() {
 	*x = Timestamp{}
-	mi := &file_protocols_spotify_proto_msgTypes[3]
+	mi := &file_protocols_spotify_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -255,7 +420,7 @@ func (x *Timestamp) String() string {
 func (*Timestamp) ProtoMessage() {}
 
 func (x *Timestamp) ProtoReflect() protoreflect.Message {
-	mi := &file_protocols_spotify_proto_msgTypes[3]
+	mi := &file_protocols_spotify_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,7 +433,7 @@ func (x *Timestamp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Timestamp.ProtoReflect.Descriptor instead.
 func (*Timestamp) Descriptor() ([]byte, []int) {
-	return file_protocols_spotify_proto_rawDescGZIP(), []int{3}
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Timestamp) GetProgress() int64 {
@@ -289,13 +454,18 @@ type Artist struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	URL           string                 `protobuf:"bytes,2,opt,name=URL,proto3" json:"URL,omitempty"`
+	ID            string                 `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	ImageURL      string                 `protobuf:"bytes,4,opt,name=imageURL,proto3" json:"imageURL,omitempty"`
+	Genres        []string               `protobuf:"bytes,5,rep,name=genres,proto3" json:"genres,omitempty"`
+	Followers     int64                  `protobuf:"varint,6,opt,name=followers,proto3" json:"followers,omitempty"`
+	Popularity    int64                  `protobuf:"varint,7,opt,name=popularity,proto3" json:"popularity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Artist) Reset() {
 	*x = Artist{}
-	mi := &file_protocols_spotify_proto_msgTypes[4]
+	mi := &file_protocols_spotify_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -307,7 +477,7 @@ func (x *Artist) String() string {
 func (*Artist) ProtoMessage() {}
 
 func (x *Artist) ProtoReflect() protoreflect.Message {
-	mi := &file_protocols_spotify_proto_msgTypes[4]
+	mi := &file_protocols_spotify_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,7 +490,7 @@ func (x *Artist) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Artist.ProtoReflect.Descriptor instead.
 func (*Artist) Descriptor() ([]byte, []int) {
-	return file_protocols_spotify_proto_rawDescGZIP(), []int{4}
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Artist) GetName() string {
@@ -337,19 +507,55 @@ func (x *Artist) GetURL() string {
 	return ""
 }
 
+func (x *Artist) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Artist) GetImageURL() string {
+	if x != nil {
+		return x.ImageURL
+	}
+	return ""
+}
+
+func (x *Artist) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *Artist) GetFollowers() int64 {
+	if x != nil {
+		return x.Followers
+	}
+	return 0
+}
+
+func (x *Artist) GetPopularity() int64 {
+	if x != nil {
+		return x.Popularity
+	}
+	return 0
+}
+
 type Album struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ImageURL      string                 `protobuf:"bytes,1,opt,name=imageURL,proto3" json:"imageURL,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	ID            string                 `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
 	URL           string                 `protobuf:"bytes,4,opt,name=URL,proto3" json:"URL,omitempty"`
+	ReleaseDate   string                 `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Album) Reset() {
 	*x = Album{}
-	mi := &file_protocols_spotify_proto_msgTypes[5]
+	mi := &file_protocols_spotify_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +567,7 @@ func (x *Album) String() string {
 func (*Album) ProtoMessage() {}
 
 func (x *Album) ProtoReflect() protoreflect.Message {
-	mi := &file_protocols_spotify_proto_msgTypes[5]
+	mi := &file_protocols_spotify_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +580,7 @@ func (x *Album) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Album.ProtoReflect.Descriptor instead.
 func (*Album) Descriptor() ([]byte, []int) {
-	return file_protocols_spotify_proto_rawDescGZIP(), []int{5}
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Album) GetImageURL() string {
@@ -405,48 +611,1841 @@ func (x *Album) GetURL() string {
 	return ""
 }
 
-var File_protocols_spotify_proto protoreflect.FileDescriptor
+func (x *Album) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
 
-const file_protocols_spotify_proto_rawDesc = "" +
-	"\n" +
-	"\x17protocols/spotify.proto\x12\tprotocols\"\x19\n" +
-	"\aRequest\x12\x0e\n" +
-	"\x02ID\x18\x01 \x01(\tR\x02ID\"\x8c\x01\n" +
-	"\aReponse\x12\x0e\n" +
-	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\f\n" +
-	"\x01E\x18\x02 \x01(\tR\x01E\x12+\n" +
-	"\x05track\x18\x03 \x01(\v2\x10.protocols.TrackH\x00R\x05track\x88\x01\x01\x12\x1f\n" +
-	"\bprogress\x18\x04 \x01(\x03H\x01R\bprogress\x88\x01\x01B\b\n" +
-	"\x06_trackB\v\n" +
-	"\t_progress\"\xa8\x02\n" +
-	"\x05Track\x12&\n" +
-	"\x05album\x18\x01 \x01(\v2\x10.protocols.AlbumR\x05album\x12)\n" +
-	"\x06artist\x18\x02 \x03(\v2\x11.protocols.ArtistR\x06artist\x12\x0e\n" +
-	"\x02ID\x18\x03 \x01(\tR\x02ID\x12\x1d\n" +
-	"\n" +
-	"is_playing\x18\x05 \x01(\bR\tisPlaying\x12 \n" +
-	"\tplayed_at\x18\x06 \x01(\x03H\x00R\bplayedAt\x88\x01\x01\x127\n" +
-	"\ttimestamp\x18\a \x01(\v2\x14.protocols.TimestampH\x01R\ttimestamp\x88\x01\x01\x12\x14\n" +
-	"\x05title\x18\b \x01(\tR\x05title\x12\x10\n" +
-	"\x03URL\x18\t \x01(\tR\x03URLB\f\n" +
-	"\n" +
-	"_played_atB\f\n" +
-	"\n" +
-	"_timestamp\"C\n" +
-	"\tTimestamp\x12\x1a\n" +
-	"\bprogress\x18\x01 \x01(\x03R\bprogress\x12\x1a\n" +
-	"\bduration\x18\x02 \x01(\x03R\bduration\".\n" +
-	"\x06Artist\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
-	"\x03URL\x18\x02 \x01(\tR\x03URL\"Y\n" +
-	"\x05Album\x12\x1a\n" +
-	"\bimageURL\x18\x01 \x01(\tR\bimageURL\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x0e\n" +
-	"\x02ID\x18\x03 \x01(\tR\x02ID\x12\x10\n" +
-	"\x03URL\x18\x04 \x01(\tR\x03URL2q\n" +
-	"\aSpotify\x120\n" +
-	"\bGetTrack\x12\x12.protocols.Request\x1a\x10.protocols.Track\x124\n" +
-	"\bOnListen\x12\x12.protocols.Request\x1a\x12.protocols.Reponse0\x01B\x13Z\x11spotify/protocolsb\x06proto3"
+type Show struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Publisher     string                 `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	ImageURL      string                 `protobuf:"bytes,4,opt,name=imageURL,proto3" json:"imageURL,omitempty"`
+	URL           string                 `protobuf:"bytes,5,opt,name=URL,proto3" json:"URL,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Show) Reset() {
+	*x = Show{}
+	mi := &file_protocols_spotify_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Show) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Show) ProtoMessage() {}
+
+func (x *Show) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Show.ProtoReflect.Descriptor instead.
+func (*Show) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Show) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Show) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Show) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *Show) GetImageURL() string {
+	if x != nil {
+		return x.ImageURL
+	}
+	return ""
+}
+
+func (x *Show) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+type State struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Track         *Track                 `protobuf:"bytes,1,opt,name=track,proto3,oneof" json:"track,omitempty"`
+	Player        *Player                `protobuf:"bytes,2,opt,name=player,proto3,oneof" json:"player,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_protocols_spotify_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *State) GetTrack() *Track {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *State) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3,oneof" json:"device,omitempty"`
+	Shuffle       bool                   `protobuf:"varint,2,opt,name=shuffle,proto3" json:"shuffle,omitempty"`
+	Repeat        string                 `protobuf:"bytes,3,opt,name=repeat,proto3" json:"repeat,omitempty"`
+	Context       *Context               `protobuf:"bytes,4,opt,name=context,proto3,oneof" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_protocols_spotify_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Player) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *Player) GetShuffle() bool {
+	if x != nil {
+		return x.Shuffle
+	}
+	return false
+}
+
+func (x *Player) GetRepeat() string {
+	if x != nil {
+		return x.Repeat
+	}
+	return ""
+}
+
+func (x *Player) GetContext() *Context {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Volume        int64                  `protobuf:"varint,4,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_protocols_spotify_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Device) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Device) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type Context struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	URI           string                 `protobuf:"bytes,2,opt,name=URI,proto3" json:"URI,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	URL           string                 `protobuf:"bytes,4,opt,name=URL,proto3" json:"URL,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Context) Reset() {
+	*x = Context{}
+	mi := &file_protocols_spotify_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Context) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Context) ProtoMessage() {}
+
+func (x *Context) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Context.ProtoReflect.Descriptor instead.
+func (*Context) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Context) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Context) GetURI() string {
+	if x != nil {
+		return x.URI
+	}
+	return ""
+}
+
+func (x *Context) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Context) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+type Queue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Current       *Track                 `protobuf:"bytes,1,opt,name=current,proto3,oneof" json:"current,omitempty"`
+	Items         []*Track               `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Queue) Reset() {
+	*x = Queue{}
+	mi := &file_protocols_spotify_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Queue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Queue) ProtoMessage() {}
+
+func (x *Queue) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Queue.ProtoReflect.Descriptor instead.
+func (*Queue) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Queue) GetCurrent() *Track {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *Queue) GetItems() []*Track {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *int64                 `protobuf:"varint,1,opt,name=from,proto3,oneof" json:"from,omitempty"`
+	To            *int64                 `protobuf:"varint,2,opt,name=to,proto3,oneof" json:"to,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int64                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Artist        string                 `protobuf:"bytes,5,opt,name=artist,proto3" json:"artist,omitempty"`
+	Album         string                 `protobuf:"bytes,6,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_protocols_spotify_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HistoryRequest) GetFrom() int64 {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetTo() int64 {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetAlbum() string {
+	if x != nil {
+		return x.Album
+	}
+	return ""
+}
+
+type Play struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Track         *Track                 `protobuf:"bytes,1,opt,name=track,proto3" json:"track,omitempty"`
+	PlayedAt      int64                  `protobuf:"varint,2,opt,name=played_at,json=playedAt,proto3" json:"played_at,omitempty"`
+	MsPlayed      int64                  `protobuf:"varint,3,opt,name=ms_played,json=msPlayed,proto3" json:"ms_played,omitempty"`
+	Skipped       bool                   `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	ReasonEnd     string                 `protobuf:"bytes,5,opt,name=reason_end,json=reasonEnd,proto3" json:"reason_end,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Play) Reset() {
+	*x = Play{}
+	mi := &file_protocols_spotify_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Play) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Play) ProtoMessage() {}
+
+func (x *Play) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Play.ProtoReflect.Descriptor instead.
+func (*Play) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Play) GetTrack() *Track {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *Play) GetPlayedAt() int64 {
+	if x != nil {
+		return x.PlayedAt
+	}
+	return 0
+}
+
+func (x *Play) GetMsPlayed() int64 {
+	if x != nil {
+		return x.MsPlayed
+	}
+	return 0
+}
+
+func (x *Play) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *Play) GetReasonEnd() string {
+	if x != nil {
+		return x.ReasonEnd
+	}
+	return ""
+}
+
+func (x *Play) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type HistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plays         []*Play                `protobuf:"bytes,1,rep,name=plays,proto3" json:"plays,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HistoryResponse) GetPlays() []*Play {
+	if x != nil {
+		return x.Plays
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *int64                 `protobuf:"varint,1,opt,name=from,proto3,oneof" json:"from,omitempty"`
+	To            *int64                 `protobuf:"varint,2,opt,name=to,proto3,oneof" json:"to,omitempty"`
+	Limit         int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Bucket        string                 `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_protocols_spotify_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StatsRequest) GetFrom() int64 {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return 0
+}
+
+func (x *StatsRequest) GetTo() int64 {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return 0
+}
+
+func (x *StatsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *StatsRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type TopItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Track         *Track                 `protobuf:"bytes,1,opt,name=track,proto3,oneof" json:"track,omitempty"`
+	Artist        *Artist                `protobuf:"bytes,2,opt,name=artist,proto3,oneof" json:"artist,omitempty"`
+	Album         *Album                 `protobuf:"bytes,3,opt,name=album,proto3,oneof" json:"album,omitempty"`
+	Plays         int64                  `protobuf:"varint,4,opt,name=plays,proto3" json:"plays,omitempty"`
+	MsPlayed      int64                  `protobuf:"varint,5,opt,name=ms_played,json=msPlayed,proto3" json:"ms_played,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopItem) Reset() {
+	*x = TopItem{}
+	mi := &file_protocols_spotify_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopItem) ProtoMessage() {}
+
+func (x *TopItem) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopItem.ProtoReflect.Descriptor instead.
+func (*TopItem) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TopItem) GetTrack() *Track {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *TopItem) GetArtist() *Artist {
+	if x != nil {
+		return x.Artist
+	}
+	return nil
+}
+
+func (x *TopItem) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+func (x *TopItem) GetPlays() int64 {
+	if x != nil {
+		return x.Plays
+	}
+	return 0
+}
+
+func (x *TopItem) GetMsPlayed() int64 {
+	if x != nil {
+		return x.MsPlayed
+	}
+	return 0
+}
+
+type TopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TopItem             `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopResponse) Reset() {
+	*x = TopResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopResponse) ProtoMessage() {}
+
+func (x *TopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopResponse.ProtoReflect.Descriptor instead.
+func (*TopResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TopResponse) GetItems() []*TopItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type Bucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	Plays         int64                  `protobuf:"varint,2,opt,name=plays,proto3" json:"plays,omitempty"`
+	MsPlayed      int64                  `protobuf:"varint,3,opt,name=ms_played,json=msPlayed,proto3" json:"ms_played,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_protocols_spotify_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Bucket) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Bucket) GetPlays() int64 {
+	if x != nil {
+		return x.Plays
+	}
+	return 0
+}
+
+func (x *Bucket) GetMsPlayed() int64 {
+	if x != nil {
+		return x.MsPlayed
+	}
+	return 0
+}
+
+type ListeningTimeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*Bucket              `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Plays         int64                  `protobuf:"varint,2,opt,name=plays,proto3" json:"plays,omitempty"`
+	MsPlayed      int64                  `protobuf:"varint,3,opt,name=ms_played,json=msPlayed,proto3" json:"ms_played,omitempty"`
+	Timezone      string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListeningTimeResponse) Reset() {
+	*x = ListeningTimeResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListeningTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListeningTimeResponse) ProtoMessage() {}
+
+func (x *ListeningTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListeningTimeResponse.ProtoReflect.Descriptor instead.
+func (*ListeningTimeResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListeningTimeResponse) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *ListeningTimeResponse) GetPlays() int64 {
+	if x != nil {
+		return x.Plays
+	}
+	return 0
+}
+
+func (x *ListeningTimeResponse) GetMsPlayed() int64 {
+	if x != nil {
+		return x.MsPlayed
+	}
+	return 0
+}
+
+func (x *ListeningTimeResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type LibraryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeRange     string                 `protobuf:"bytes,1,opt,name=time_range,json=timeRange,proto3" json:"time_range,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LibraryRequest) Reset() {
+	*x = LibraryRequest{}
+	mi := &file_protocols_spotify_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LibraryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LibraryRequest) ProtoMessage() {}
+
+func (x *LibraryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LibraryRequest.ProtoReflect.Descriptor instead.
+func (*LibraryRequest) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LibraryRequest) GetTimeRange() string {
+	if x != nil {
+		return x.TimeRange
+	}
+	return ""
+}
+
+func (x *LibraryRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LibraryRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type Playlist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ImageURL      string                 `protobuf:"bytes,4,opt,name=imageURL,proto3" json:"imageURL,omitempty"`
+	URL           string                 `protobuf:"bytes,5,opt,name=URL,proto3" json:"URL,omitempty"`
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	Tracks        int64                  `protobuf:"varint,7,opt,name=tracks,proto3" json:"tracks,omitempty"`
+	Public        bool                   `protobuf:"varint,8,opt,name=public,proto3" json:"public,omitempty"`
+	Collaborative bool                   `protobuf:"varint,9,opt,name=collaborative,proto3" json:"collaborative,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Playlist) Reset() {
+	*x = Playlist{}
+	mi := &file_protocols_spotify_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Playlist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Playlist) ProtoMessage() {}
+
+func (x *Playlist) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Playlist.ProtoReflect.Descriptor instead.
+func (*Playlist) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Playlist) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Playlist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Playlist) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Playlist) GetImageURL() string {
+	if x != nil {
+		return x.ImageURL
+	}
+	return ""
+}
+
+func (x *Playlist) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+func (x *Playlist) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Playlist) GetTracks() int64 {
+	if x != nil {
+		return x.Tracks
+	}
+	return 0
+}
+
+func (x *Playlist) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *Playlist) GetCollaborative() bool {
+	if x != nil {
+		return x.Collaborative
+	}
+	return false
+}
+
+type ArtistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artists       []*Artist              `protobuf:"bytes,1,rep,name=artists,proto3" json:"artists,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtistsResponse) Reset() {
+	*x = ArtistsResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtistsResponse) ProtoMessage() {}
+
+func (x *ArtistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtistsResponse.ProtoReflect.Descriptor instead.
+func (*ArtistsResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ArtistsResponse) GetArtists() []*Artist {
+	if x != nil {
+		return x.Artists
+	}
+	return nil
+}
+
+func (x *ArtistsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type TracksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tracks        []*Track               `protobuf:"bytes,1,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TracksResponse) Reset() {
+	*x = TracksResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TracksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TracksResponse) ProtoMessage() {}
+
+func (x *TracksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TracksResponse.ProtoReflect.Descriptor instead.
+func (*TracksResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TracksResponse) GetTracks() []*Track {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *TracksResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PlaylistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Playlists     []*Playlist            `protobuf:"bytes,1,rep,name=playlists,proto3" json:"playlists,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaylistsResponse) Reset() {
+	*x = PlaylistsResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaylistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaylistsResponse) ProtoMessage() {}
+
+func (x *PlaylistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaylistsResponse.ProtoReflect.Descriptor instead.
+func (*PlaylistsResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PlaylistsResponse) GetPlaylists() []*Playlist {
+	if x != nil {
+		return x.Playlists
+	}
+	return nil
+}
+
+func (x *PlaylistsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceID      string                 `protobuf:"bytes,1,opt,name=deviceID,proto3" json:"deviceID,omitempty"`
+	ContextURI    *string                `protobuf:"bytes,2,opt,name=contextURI,proto3,oneof" json:"contextURI,omitempty"`
+	URIs          []string               `protobuf:"bytes,3,rep,name=URIs,proto3" json:"URIs,omitempty"`
+	Position      *int64                 `protobuf:"varint,4,opt,name=position,proto3,oneof" json:"position,omitempty"`
+	Volume        *int64                 `protobuf:"varint,5,opt,name=volume,proto3,oneof" json:"volume,omitempty"`
+	Shuffle       *bool                  `protobuf:"varint,6,opt,name=shuffle,proto3,oneof" json:"shuffle,omitempty"`
+	Repeat        string                 `protobuf:"bytes,7,opt,name=repeat,proto3" json:"repeat,omitempty"`
+	URI           string                 `protobuf:"bytes,8,opt,name=URI,proto3" json:"URI,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerRequest) Reset() {
+	*x = PlayerRequest{}
+	mi := &file_protocols_spotify_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerRequest) ProtoMessage() {}
+
+func (x *PlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRequest.ProtoReflect.Descriptor instead.
+func (*PlayerRequest) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PlayerRequest) GetDeviceID() string {
+	if x != nil {
+		return x.DeviceID
+	}
+	return ""
+}
+
+func (x *PlayerRequest) GetContextURI() string {
+	if x != nil && x.ContextURI != nil {
+		return *x.ContextURI
+	}
+	return ""
+}
+
+func (x *PlayerRequest) GetURIs() []string {
+	if x != nil {
+		return x.URIs
+	}
+	return nil
+}
+
+func (x *PlayerRequest) GetPosition() int64 {
+	if x != nil && x.Position != nil {
+		return *x.Position
+	}
+	return 0
+}
+
+func (x *PlayerRequest) GetVolume() int64 {
+	if x != nil && x.Volume != nil {
+		return *x.Volume
+	}
+	return 0
+}
+
+func (x *PlayerRequest) GetShuffle() bool {
+	if x != nil && x.Shuffle != nil {
+		return *x.Shuffle
+	}
+	return false
+}
+
+func (x *PlayerRequest) GetRepeat() string {
+	if x != nil {
+		return x.Repeat
+	}
+	return ""
+}
+
+func (x *PlayerRequest) GetURI() string {
+	if x != nil {
+		return x.URI
+	}
+	return ""
+}
+
+type Delivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	URL           string                 `protobuf:"bytes,3,opt,name=URL,proto3" json:"URL,omitempty"`
+	Attempts      int64                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	StatusCode    int64                  `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Delivery) Reset() {
+	*x = Delivery{}
+	mi := &file_protocols_spotify_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Delivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Delivery) ProtoMessage() {}
+
+func (x *Delivery) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Delivery.ProtoReflect.Descriptor instead.
+func (*Delivery) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Delivery) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Delivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Delivery) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+func (x *Delivery) GetAttempts() int64 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Delivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Delivery) GetStatusCode() int64 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *Delivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Delivery) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Delivery) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	URL           string                 `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+	Events        []string               `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	Pending       int64                  `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	Delivered     int64                  `protobuf:"varint,4,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Failed        int64                  `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	Recent        []*Delivery            `protobuf:"bytes,6,rep,name=recent,proto3" json:"recent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_protocols_spotify_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Webhook) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetPending() int64 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *Webhook) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *Webhook) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *Webhook) GetRecent() []*Delivery {
+	if x != nil {
+		return x.Recent
+	}
+	return nil
+}
+
+type WebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhooksResponse) Reset() {
+	*x = WebhooksResponse{}
+	mi := &file_protocols_spotify_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhooksResponse) ProtoMessage() {}
+
+func (x *WebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocols_spotify_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhooksResponse.ProtoReflect.Descriptor instead.
+func (*WebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_protocols_spotify_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+var File_protocols_spotify_proto protoreflect.FileDescriptor
+
+const file_protocols_spotify_proto_rawDesc = "" +
+	"\n" +
+	"\x17protocols/spotify.proto\x12\tprotocols\"\x19\n" +
+	"\aRequest\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\"\xed\x02\n" +
+	"\aReponse\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\f\n" +
+	"\x01E\x18\x02 \x01(\tR\x01E\x12+\n" +
+	"\x05track\x18\x03 \x01(\v2\x10.protocols.TrackH\x00R\x05track\x88\x01\x01\x12\x1f\n" +
+	"\bprogress\x18\x04 \x01(\x03H\x01R\bprogress\x88\x01\x01\x12+\n" +
+	"\x05queue\x18\x05 \x01(\v2\x10.protocols.QueueH\x02R\x05queue\x88\x01\x01\x123\n" +
+	"\x05trace\x18\x06 \x03(\v2\x1d.protocols.Reponse.TraceEntryR\x05trace\x12.\n" +
+	"\x06player\x18\a \x01(\v2\x11.protocols.PlayerH\x03R\x06player\x88\x01\x01\x1a8\n" +
+	"\n" +
+	"TraceEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\b\n" +
+	"\x06_trackB\v\n" +
+	"\t_progressB\b\n" +
+	"\x06_queueB\t\n" +
+	"\a_player\"6\n" +
+	"\x06Demand\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1c\n" +
+	"\tlisteners\x18\x02 \x01(\x03R\tlisteners\"\x8d\x05\n" +
+	"\x05Track\x12&\n" +
+	"\x05album\x18\x01 \x01(\v2\x10.protocols.AlbumR\x05album\x12)\n" +
+	"\x06artist\x18\x02 \x03(\v2\x11.protocols.ArtistR\x06artist\x12\x0e\n" +
+	"\x02ID\x18\x03 \x01(\tR\x02ID\x12\x1d\n" +
+	"\n" +
+	"is_playing\x18\x05 \x01(\bR\tisPlaying\x12 \n" +
+	"\tplayed_at\x18\x06 \x01(\x03H\x00R\bplayedAt\x88\x01\x01\x127\n" +
+	"\ttimestamp\x18\a \x01(\v2\x14.protocols.TimestampH\x01R\ttimestamp\x88\x01\x01\x12\x14\n" +
+	"\x05title\x18\b \x01(\tR\x05title\x12\x10\n" +
+	"\x03URL\x18\t \x01(\tR\x03URL\x12\x12\n" +
+	"\x04type\x18\n" +
+	" \x01(\tR\x04type\x12(\n" +
+	"\x04show\x18\v \x01(\v2\x0f.protocols.ShowH\x02R\x04show\x88\x01\x01\x12\x19\n" +
+	"\bis_local\x18\f \x01(\bR\aisLocal\x12\x1a\n" +
+	"\bduration\x18\x0e \x01(\x03R\bduration\x12\x1a\n" +
+	"\bexplicit\x18\x0f \x01(\bR\bexplicit\x12\x1e\n" +
+	"\n" +
+	"popularity\x18\x10 \x01(\x03R\n" +
+	"popularity\x12\x12\n" +
+	"\x04ISRC\x18\x11 \x01(\tR\x04ISRC\x12!\n" +
+	"\ftrack_number\x18\x12 \x01(\x03R\vtrackNumber\x12\x1f\n" +
+	"\vdisc_number\x18\x13 \x01(\x03R\n" +
+	"discNumber\x12\x1e\n" +
+	"\n" +
+	"previewURL\x18\x14 \x01(\tR\n" +
+	"previewURL\x12\x1e\n" +
+	"\badded_at\x18\x15 \x01(\x03H\x03R\aaddedAt\x88\x01\x01B\f\n" +
+	"\n" +
+	"_played_atB\f\n" +
+	"\n" +
+	"_timestampB\a\n" +
+	"\x05_showB\v\n" +
+	"\t_added_atJ\x04\b\r\x10\x0e\"C\n" +
+	"\tTimestamp\x12\x1a\n" +
+	"\bprogress\x18\x01 \x01(\x03R\bprogress\x12\x1a\n" +
+	"\bduration\x18\x02 \x01(\x03R\bduration\"\xb0\x01\n" +
+	"\x06Artist\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03URL\x18\x02 \x01(\tR\x03URL\x12\x0e\n" +
+	"\x02ID\x18\x03 \x01(\tR\x02ID\x12\x1a\n" +
+	"\bimageURL\x18\x04 \x01(\tR\bimageURL\x12\x16\n" +
+	"\x06genres\x18\x05 \x03(\tR\x06genres\x12\x1c\n" +
+	"\tfollowers\x18\x06 \x01(\x03R\tfollowers\x12\x1e\n" +
+	"\n" +
+	"popularity\x18\a \x01(\x03R\n" +
+	"popularity\"|\n" +
+	"\x05Album\x12\x1a\n" +
+	"\bimageURL\x18\x01 \x01(\tR\bimageURL\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02ID\x18\x03 \x01(\tR\x02ID\x12\x10\n" +
+	"\x03URL\x18\x04 \x01(\tR\x03URL\x12!\n" +
+	"\frelease_date\x18\x05 \x01(\tR\vreleaseDate\"v\n" +
+	"\x04Show\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tpublisher\x18\x03 \x01(\tR\tpublisher\x12\x1a\n" +
+	"\bimageURL\x18\x04 \x01(\tR\bimageURL\x12\x10\n" +
+	"\x03URL\x18\x05 \x01(\tR\x03URL\"y\n" +
+	"\x05State\x12+\n" +
+	"\x05track\x18\x01 \x01(\v2\x10.protocols.TrackH\x00R\x05track\x88\x01\x01\x12.\n" +
+	"\x06player\x18\x02 \x01(\v2\x11.protocols.PlayerH\x01R\x06player\x88\x01\x01B\b\n" +
+	"\x06_trackB\t\n" +
+	"\a_player\"\xb4\x01\n" +
+	"\x06Player\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x11.protocols.DeviceH\x00R\x06device\x88\x01\x01\x12\x18\n" +
+	"\ashuffle\x18\x02 \x01(\bR\ashuffle\x12\x16\n" +
+	"\x06repeat\x18\x03 \x01(\tR\x06repeat\x121\n" +
+	"\acontext\x18\x04 \x01(\v2\x12.protocols.ContextH\x01R\acontext\x88\x01\x01B\t\n" +
+	"\a_deviceB\n" +
+	"\n" +
+	"\b_context\"X\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06volume\x18\x04 \x01(\x03R\x06volume\"U\n" +
+	"\aContext\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x10\n" +
+	"\x03URI\x18\x02 \x01(\tR\x03URI\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x10\n" +
+	"\x03URL\x18\x04 \x01(\tR\x03URL\"l\n" +
+	"\x05Queue\x12/\n" +
+	"\acurrent\x18\x01 \x01(\v2\x10.protocols.TrackH\x00R\acurrent\x88\x01\x01\x12&\n" +
+	"\x05items\x18\x02 \x03(\v2\x10.protocols.TrackR\x05itemsB\n" +
+	"\n" +
+	"\b_current\"\xaa\x01\n" +
+	"\x0eHistoryRequest\x12\x17\n" +
+	"\x04from\x18\x01 \x01(\x03H\x00R\x04from\x88\x01\x01\x12\x13\n" +
+	"\x02to\x18\x02 \x01(\x03H\x01R\x02to\x88\x01\x01\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06artist\x18\x05 \x01(\tR\x06artist\x12\x14\n" +
+	"\x05album\x18\x06 \x01(\tR\x05albumB\a\n" +
+	"\x05_fromB\x05\n" +
+	"\x03_to\"\xb9\x01\n" +
+	"\x04Play\x12&\n" +
+	"\x05track\x18\x01 \x01(\v2\x10.protocols.TrackR\x05track\x12\x1b\n" +
+	"\tplayed_at\x18\x02 \x01(\x03R\bplayedAt\x12\x1b\n" +
+	"\tms_played\x18\x03 \x01(\x03R\bmsPlayed\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\bR\askipped\x12\x1d\n" +
+	"\n" +
+	"reason_end\x18\x05 \x01(\tR\treasonEnd\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\"P\n" +
+	"\x0fHistoryResponse\x12%\n" +
+	"\x05plays\x18\x01 \x03(\v2\x0f.protocols.PlayR\x05plays\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"z\n" +
+	"\fStatsRequest\x12\x17\n" +
+	"\x04from\x18\x01 \x01(\x03H\x00R\x04from\x88\x01\x01\x12\x13\n" +
+	"\x02to\x18\x02 \x01(\x03H\x01R\x02to\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucketB\a\n" +
+	"\x05_fromB\x05\n" +
+	"\x03_to\"\xe5\x01\n" +
+	"\aTopItem\x12+\n" +
+	"\x05track\x18\x01 \x01(\v2\x10.protocols.TrackH\x00R\x05track\x88\x01\x01\x12.\n" +
+	"\x06artist\x18\x02 \x01(\v2\x11.protocols.ArtistH\x01R\x06artist\x88\x01\x01\x12+\n" +
+	"\x05album\x18\x03 \x01(\v2\x10.protocols.AlbumH\x02R\x05album\x88\x01\x01\x12\x14\n" +
+	"\x05plays\x18\x04 \x01(\x03R\x05plays\x12\x1b\n" +
+	"\tms_played\x18\x05 \x01(\x03R\bmsPlayedB\b\n" +
+	"\x06_trackB\t\n" +
+	"\a_artistB\b\n" +
+	"\x06_album\"7\n" +
+	"\vTopResponse\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.protocols.TopItemR\x05items\"Q\n" +
+	"\x06Bucket\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x14\n" +
+	"\x05plays\x18\x02 \x01(\x03R\x05plays\x12\x1b\n" +
+	"\tms_played\x18\x03 \x01(\x03R\bmsPlayed\"\x93\x01\n" +
+	"\x15ListeningTimeResponse\x12+\n" +
+	"\abuckets\x18\x01 \x03(\v2\x11.protocols.BucketR\abuckets\x12\x14\n" +
+	"\x05plays\x18\x02 \x01(\x03R\x05plays\x12\x1b\n" +
+	"\tms_played\x18\x03 \x01(\x03R\bmsPlayed\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\"]\n" +
+	"\x0eLibraryRequest\x12\x1d\n" +
+	"\n" +
+	"time_range\x18\x01 \x01(\tR\ttimeRange\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\"\xea\x01\n" +
+	"\bPlaylist\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bimageURL\x18\x04 \x01(\tR\bimageURL\x12\x10\n" +
+	"\x03URL\x18\x05 \x01(\tR\x03URL\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x16\n" +
+	"\x06tracks\x18\a \x01(\x03R\x06tracks\x12\x16\n" +
+	"\x06public\x18\b \x01(\bR\x06public\x12$\n" +
+	"\rcollaborative\x18\t \x01(\bR\rcollaborative\"T\n" +
+	"\x0fArtistsResponse\x12+\n" +
+	"\aartists\x18\x01 \x03(\v2\x11.protocols.ArtistR\aartists\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"P\n" +
+	"\x0eTracksResponse\x12(\n" +
+	"\x06tracks\x18\x01 \x03(\v2\x10.protocols.TrackR\x06tracks\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\\\n" +
+	"\x11PlaylistsResponse\x121\n" +
+	"\tplaylists\x18\x01 \x03(\v2\x13.protocols.PlaylistR\tplaylists\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\x9e\x02\n" +
+	"\rPlayerRequest\x12\x1a\n" +
+	"\bdeviceID\x18\x01 \x01(\tR\bdeviceID\x12#\n" +
+	"\n" +
+	"contextURI\x18\x02 \x01(\tH\x00R\n" +
+	"contextURI\x88\x01\x01\x12\x12\n" +
+	"\x04URIs\x18\x03 \x03(\tR\x04URIs\x12\x1f\n" +
+	"\bposition\x18\x04 \x01(\x03H\x01R\bposition\x88\x01\x01\x12\x1b\n" +
+	"\x06volume\x18\x05 \x01(\x03H\x02R\x06volume\x88\x01\x01\x12\x1d\n" +
+	"\ashuffle\x18\x06 \x01(\bH\x03R\ashuffle\x88\x01\x01\x12\x16\n" +
+	"\x06repeat\x18\a \x01(\tR\x06repeat\x12\x10\n" +
+	"\x03URI\x18\b \x01(\tR\x03URIB\r\n" +
+	"\v_contextURIB\v\n" +
+	"\t_positionB\t\n" +
+	"\a_volumeB\n" +
+	"\n" +
+	"\b_shuffle\"\xeb\x01\n" +
+	"\bDelivery\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x10\n" +
+	"\x03URL\x18\x03 \x01(\tR\x03URL\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x03R\battempts\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1f\n" +
+	"\vstatus_code\x18\x06 \x01(\x03R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\"\xb0\x01\n" +
+	"\aWebhook\x12\x10\n" +
+	"\x03URL\x18\x01 \x01(\tR\x03URL\x12\x16\n" +
+	"\x06events\x18\x02 \x03(\tR\x06events\x12\x18\n" +
+	"\apending\x18\x03 \x01(\x03R\apending\x12\x1c\n" +
+	"\tdelivered\x18\x04 \x01(\x03R\tdelivered\x12\x16\n" +
+	"\x06failed\x18\x05 \x01(\x03R\x06failed\x12+\n" +
+	"\x06recent\x18\x06 \x03(\v2\x13.protocols.DeliveryR\x06recent\"B\n" +
+	"\x10WebhooksResponse\x12.\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x12.protocols.WebhookR\bwebhooks2\xe8\n" +
+	"\n" +
+	"\aSpotify\x120\n" +
+	"\bGetTrack\x12\x12.protocols.Request\x1a\x10.protocols.State\x124\n" +
+	"\bOnListen\x12\x12.protocols.Request\x1a\x12.protocols.Reponse0\x01\x121\n" +
+	"\tSetDemand\x12\x11.protocols.Demand\x1a\x11.protocols.Demand\x12D\n" +
+	"\vListHistory\x12\x19.protocols.HistoryRequest\x1a\x1a.protocols.HistoryResponse\x12<\n" +
+	"\tTopTracks\x12\x17.protocols.StatsRequest\x1a\x16.protocols.TopResponse\x12=\n" +
+	"\n" +
+	"TopArtists\x12\x17.protocols.StatsRequest\x1a\x16.protocols.TopResponse\x12<\n" +
+	"\tTopAlbums\x12\x17.protocols.StatsRequest\x1a\x16.protocols.TopResponse\x12J\n" +
+	"\rListeningTime\x12\x17.protocols.StatsRequest\x1a .protocols.ListeningTimeResponse\x12F\n" +
+	"\rGetTopArtists\x12\x19.protocols.LibraryRequest\x1a\x1a.protocols.ArtistsResponse\x12D\n" +
+	"\fGetTopTracks\x12\x19.protocols.LibraryRequest\x1a\x19.protocols.TracksResponse\x12F\n" +
+	"\x0eGetSavedTracks\x12\x19.protocols.LibraryRequest\x1a\x19.protocols.TracksResponse\x12G\n" +
+	"\fGetPlaylists\x12\x19.protocols.LibraryRequest\x1a\x1c.protocols.PlaylistsResponse\x122\n" +
+	"\x04Play\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x123\n" +
+	"\x05Pause\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x122\n" +
+	"\x04Next\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x126\n" +
+	"\bPrevious\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x122\n" +
+	"\x04Seek\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x124\n" +
+	"\x06Volume\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x125\n" +
+	"\aShuffle\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x124\n" +
+	"\x06Repeat\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x128\n" +
+	"\n" +
+	"AddToQueue\x12\x18.protocols.PlayerRequest\x1a\x10.protocols.State\x120\n" +
+	"\bGetQueue\x12\x12.protocols.Request\x1a\x10.protocols.Queue\x12>\n" +
+	"\vGetWebhooks\x12\x12.protocols.Request\x1a\x1b.protocols.WebhooksResponseB\x13Z\x11spotify/protocolsb\x06proto3"
 
 var (
 	file_protocols_spotify_proto_rawDescOnce sync.Once
@@ -460,29 +2459,118 @@ func file_protocols_spotify_proto_rawDescGZIP() []byte {
 	return file_protocols_spotify_proto_rawDescData
 }
 
-var file_protocols_spotify_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_protocols_spotify_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_protocols_spotify_proto_goTypes = []any{
-	(*Request)(nil),   // 0: protocols.Request
-	(*Reponse)(nil),   // 1: protocols.Reponse
-	(*Track)(nil),     // 2: protocols.Track
-	(*Timestamp)(nil), // 3: protocols.Timestamp
-	(*Artist)(nil),    // 4: protocols.Artist
-	(*Album)(nil),     // 5: protocols.Album
+	(*Request)(nil),               // 0: protocols.Request
+	(*Reponse)(nil),               // 1: protocols.Reponse
+	(*Demand)(nil),                // 2: protocols.Demand
+	(*Track)(nil),                 // 3: protocols.Track
+	(*Timestamp)(nil),             // 4: protocols.Timestamp
+	(*Artist)(nil),                // 5: protocols.Artist
+	(*Album)(nil),                 // 6: protocols.Album
+	(*Show)(nil),                  // 7: protocols.Show
+	(*State)(nil),                 // 8: protocols.State
+	(*Player)(nil),                // 9: protocols.Player
+	(*Device)(nil),                // 10: protocols.Device
+	(*Context)(nil),               // 11: protocols.Context
+	(*Queue)(nil),                 // 12: protocols.Queue
+	(*HistoryRequest)(nil),        // 13: protocols.HistoryRequest
+	(*Play)(nil),                  // 14: protocols.Play
+	(*HistoryResponse)(nil),       // 15: protocols.HistoryResponse
+	(*StatsRequest)(nil),          // 16: protocols.StatsRequest
+	(*TopItem)(nil),               // 17: protocols.TopItem
+	(*TopResponse)(nil),           // 18: protocols.TopResponse
+	(*Bucket)(nil),                // 19: protocols.Bucket
+	(*ListeningTimeResponse)(nil), // 20: protocols.ListeningTimeResponse
+	(*LibraryRequest)(nil),        // 21: protocols.LibraryRequest
+	(*Playlist)(nil),              // 22: protocols.Playlist
+	(*ArtistsResponse)(nil),       // 23: protocols.ArtistsResponse
+	(*TracksResponse)(nil),        // 24: protocols.TracksResponse
+	(*PlaylistsResponse)(nil),     // 25: protocols.PlaylistsResponse
+	(*PlayerRequest)(nil),         // 26: protocols.PlayerRequest
+	(*Delivery)(nil),              // 27: protocols.Delivery
+	(*Webhook)(nil),               // 28: protocols.Webhook
+	(*WebhooksResponse)(nil),      // 29: protocols.WebhooksResponse
+	nil,                           // 30: protocols.Reponse.TraceEntry
 }
 var file_protocols_spotify_proto_depIdxs = []int32{
-	2, // 0: protocols.Reponse.track:type_name -> protocols.Track
-	5, // 1: protocols.Track.album:type_name -> protocols.Album
-	4, // 2: protocols.Track.artist:type_name -> protocols.Artist
-	3, // 3: protocols.Track.timestamp:type_name -> protocols.Timestamp
-	0, // 4: protocols.Spotify.GetTrack:input_type -> protocols.Request
-	0, // 5: protocols.Spotify.OnListen:input_type -> protocols.Request
-	2, // 6: protocols.Spotify.GetTrack:output_type -> protocols.Track
-	1, // 7: protocols.Spotify.OnListen:output_type -> protocols.Reponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	3,  // 0: protocols.Reponse.track:type_name -> protocols.Track
+	12, // 1: protocols.Reponse.queue:type_name -> protocols.Queue
+	30, // 2: protocols.Reponse.trace:type_name -> protocols.Reponse.TraceEntry
+	9,  // 3: protocols.Reponse.player:type_name -> protocols.Player
+	6,  // 4: protocols.Track.album:type_name -> protocols.Album
+	5,  // 5: protocols.Track.artist:type_name -> protocols.Artist
+	4,  // 6: protocols.Track.timestamp:type_name -> protocols.Timestamp
+	7,  // 7: protocols.Track.show:type_name -> protocols.Show
+	3,  // 8: protocols.State.track:type_name -> protocols.Track
+	9,  // 9: protocols.State.player:type_name -> protocols.Player
+	10, // 10: protocols.Player.device:type_name -> protocols.Device
+	11, // 11: protocols.Player.context:type_name -> protocols.Context
+	3,  // 12: protocols.Queue.current:type_name -> protocols.Track
+	3,  // 13: protocols.Queue.items:type_name -> protocols.Track
+	3,  // 14: protocols.Play.track:type_name -> protocols.Track
+	14, // 15: protocols.HistoryResponse.plays:type_name -> protocols.Play
+	3,  // 16: protocols.TopItem.track:type_name -> protocols.Track
+	5,  // 17: protocols.TopItem.artist:type_name -> protocols.Artist
+	6,  // 18: protocols.TopItem.album:type_name -> protocols.Album
+	17, // 19: protocols.TopResponse.items:type_name -> protocols.TopItem
+	19, // 20: protocols.ListeningTimeResponse.buckets:type_name -> protocols.Bucket
+	5,  // 21: protocols.ArtistsResponse.artists:type_name -> protocols.Artist
+	3,  // 22: protocols.TracksResponse.tracks:type_name -> protocols.Track
+	22, // 23: protocols.PlaylistsResponse.playlists:type_name -> protocols.Playlist
+	27, // 24: protocols.Webhook.recent:type_name -> protocols.Delivery
+	28, // 25: protocols.WebhooksResponse.webhooks:type_name -> protocols.Webhook
+	0,  // 26: protocols.Spotify.GetTrack:input_type -> protocols.Request
+	0,  // 27: protocols.Spotify.OnListen:input_type -> protocols.Request
+	2,  // 28: protocols.Spotify.SetDemand:input_type -> protocols.Demand
+	13, // 29: protocols.Spotify.ListHistory:input_type -> protocols.HistoryRequest
+	16, // 30: protocols.Spotify.TopTracks:input_type -> protocols.StatsRequest
+	16, // 31: protocols.Spotify.TopArtists:input_type -> protocols.StatsRequest
+	16, // 32: protocols.Spotify.TopAlbums:input_type -> protocols.StatsRequest
+	16, // 33: protocols.Spotify.ListeningTime:input_type -> protocols.StatsRequest
+	21, // 34: protocols.Spotify.GetTopArtists:input_type -> protocols.LibraryRequest
+	21, // 35: protocols.Spotify.GetTopTracks:input_type -> protocols.LibraryRequest
+	21, // 36: protocols.Spotify.GetSavedTracks:input_type -> protocols.LibraryRequest
+	21, // 37: protocols.Spotify.GetPlaylists:input_type -> protocols.LibraryRequest
+	26, // 38: protocols.Spotify.Play:input_type -> protocols.PlayerRequest
+	26, // 39: protocols.Spotify.Pause:input_type -> protocols.PlayerRequest
+	26, // 40: protocols.Spotify.Next:input_type -> protocols.PlayerRequest
+	26, // 41: protocols.Spotify.Previous:input_type -> protocols.PlayerRequest
+	26, // 42: protocols.Spotify.Seek:input_type -> protocols.PlayerRequest
+	26, // 43: protocols.Spotify.Volume:input_type -> protocols.PlayerRequest
+	26, // 44: protocols.Spotify.Shuffle:input_type -> protocols.PlayerRequest
+	26, // 45: protocols.Spotify.Repeat:input_type -> protocols.PlayerRequest
+	26, // 46: protocols.Spotify.AddToQueue:input_type -> protocols.PlayerRequest
+	0,  // 47: protocols.Spotify.GetQueue:input_type -> protocols.Request
+	0,  // 48: protocols.Spotify.GetWebhooks:input_type -> protocols.Request
+	8,  // 49: protocols.Spotify.GetTrack:output_type -> protocols.State
+	1,  // 50: protocols.Spotify.OnListen:output_type -> protocols.Reponse
+	2,  // 51: protocols.Spotify.SetDemand:output_type -> protocols.Demand
+	15, // 52: protocols.Spotify.ListHistory:output_type -> protocols.HistoryResponse
+	18, // 53: protocols.Spotify.TopTracks:output_type -> protocols.TopResponse
+	18, // 54: protocols.Spotify.TopArtists:output_type -> protocols.TopResponse
+	18, // 55: protocols.Spotify.TopAlbums:output_type -> protocols.TopResponse
+	20, // 56: protocols.Spotify.ListeningTime:output_type -> protocols.ListeningTimeResponse
+	23, // 57: protocols.Spotify.GetTopArtists:output_type -> protocols.ArtistsResponse
+	24, // 58: protocols.Spotify.GetTopTracks:output_type -> protocols.TracksResponse
+	24, // 59: protocols.Spotify.GetSavedTracks:output_type -> protocols.TracksResponse
+	25, // 60: protocols.Spotify.GetPlaylists:output_type -> protocols.PlaylistsResponse
+	8,  // 61: protocols.Spotify.Play:output_type -> protocols.State
+	8,  // 62: protocols.Spotify.Pause:output_type -> protocols.State
+	8,  // 63: protocols.Spotify.Next:output_type -> protocols.State
+	8,  // 64: protocols.Spotify.Previous:output_type -> protocols.State
+	8,  // 65: protocols.Spotify.Seek:output_type -> protocols.State
+	8,  // 66: protocols.Spotify.Volume:output_type -> protocols.State
+	8,  // 67: protocols.Spotify.Shuffle:output_type -> protocols.State
+	8,  // 68: protocols.Spotify.Repeat:output_type -> protocols.State
+	8,  // 69: protocols.Spotify.AddToQueue:output_type -> protocols.State
+	12, // 70: protocols.Spotify.GetQueue:output_type -> protocols.Queue
+	29, // 71: protocols.Spotify.GetWebhooks:output_type -> protocols.WebhooksResponse
+	49, // [49:72] is the sub-list for method output_type
+	26, // [26:49] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_protocols_spotify_proto_init() }
@@ -491,14 +2579,21 @@ func file_protocols_spotify_proto_init() {
 		return
 	}
 	file_protocols_spotify_proto_msgTypes[1].OneofWrappers = []any{}
-	file_protocols_spotify_proto_msgTypes[2].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[3].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[8].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[9].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[12].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[13].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[16].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[17].OneofWrappers = []any{}
+	file_protocols_spotify_proto_msgTypes[26].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protocols_spotify_proto_rawDesc), len(file_protocols_spotify_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,16 +19,58 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Spotify_GetTrack_FullMethodName = "/protocols.Spotify/GetTrack"
-	Spotify_OnListen_FullMethodName = "/protocols.Spotify/OnListen"
+	Spotify_GetTrack_FullMethodName       = "/protocols.Spotify/GetTrack"
+	Spotify_OnListen_FullMethodName       = "/protocols.Spotify/OnListen"
+	Spotify_SetDemand_FullMethodName      = "/protocols.Spotify/SetDemand"
+	Spotify_ListHistory_FullMethodName    = "/protocols.Spotify/ListHistory"
+	Spotify_TopTracks_FullMethodName      = "/protocols.Spotify/TopTracks"
+	Spotify_TopArtists_FullMethodName     = "/protocols.Spotify/TopArtists"
+	Spotify_TopAlbums_FullMethodName      = "/protocols.Spotify/TopAlbums"
+	Spotify_ListeningTime_FullMethodName  = "/protocols.Spotify/ListeningTime"
+	Spotify_GetTopArtists_FullMethodName  = "/protocols.Spotify/GetTopArtists"
+	Spotify_GetTopTracks_FullMethodName   = "/protocols.Spotify/GetTopTracks"
+	Spotify_GetSavedTracks_FullMethodName = "/protocols.Spotify/GetSavedTracks"
+	Spotify_GetPlaylists_FullMethodName   = "/protocols.Spotify/GetPlaylists"
+	Spotify_Play_FullMethodName           = "/protocols.Spotify/Play"
+	Spotify_Pause_FullMethodName          = "/protocols.Spotify/Pause"
+	Spotify_Next_FullMethodName           = "/protocols.Spotify/Next"
+	Spotify_Previous_FullMethodName       = "/protocols.Spotify/Previous"
+	Spotify_Seek_FullMethodName           = "/protocols.Spotify/Seek"
+	Spotify_Volume_FullMethodName         = "/protocols.Spotify/Volume"
+	Spotify_Shuffle_FullMethodName        = "/protocols.Spotify/Shuffle"
+	Spotify_Repeat_FullMethodName         = "/protocols.Spotify/Repeat"
+	Spotify_AddToQueue_FullMethodName     = "/protocols.Spotify/AddToQueue"
+	Spotify_GetQueue_FullMethodName       = "/protocols.Spotify/GetQueue"
+	Spotify_GetWebhooks_FullMethodName    = "/protocols.Spotify/GetWebhooks"
 )
 
 // SpotifyClient is the client API for Spotify service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SpotifyClient interface {
-	GetTrack(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Track, error)
+	GetTrack(ctx context.Context, in *Request, opts ...grpc.CallOption) (*State, error)
 	OnListen(ctx context.Context, in *Request, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Reponse], error)
+	SetDemand(ctx context.Context, in *Demand, opts ...grpc.CallOption) (*Demand, error)
+	ListHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	TopTracks(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	TopArtists(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	TopAlbums(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	ListeningTime(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*ListeningTimeResponse, error)
+	GetTopArtists(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*ArtistsResponse, error)
+	GetTopTracks(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*TracksResponse, error)
+	GetSavedTracks(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*TracksResponse, error)
+	GetPlaylists(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*PlaylistsResponse, error)
+	Play(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Pause(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Next(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Previous(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Seek(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Volume(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Shuffle(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	Repeat(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	AddToQueue(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error)
+	GetQueue(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Queue, error)
+	GetWebhooks(ctx context.Context, in *Request, opts ...grpc.CallOption) (*WebhooksResponse, error)
 }
 
 type spotifyClient struct {
@@ -39,9 +81,9 @@ func NewSpotifyClient(cc grpc.ClientConnInterface) SpotifyClient {
 	return &spotifyClient{cc}
 }
 
-func (c *spotifyClient) GetTrack(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Track, error) {
+func (c *spotifyClient) GetTrack(ctx context.Context, in *Request, opts ...grpc.CallOption) (*State, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Track)
+	out := new(State)
 	err := c.cc.Invoke(ctx, Spotify_GetTrack_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -68,12 +110,243 @@ func (c *spotifyClient) OnListen(ctx context.Context, in *Request, opts ...grpc.
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Spotify_OnListenClient = grpc.ServerStreamingClient[Reponse]
 
+func (c *spotifyClient) SetDemand(ctx context.Context, in *Demand, opts ...grpc.CallOption) (*Demand, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Demand)
+	err := c.cc.Invoke(ctx, Spotify_SetDemand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) ListHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, Spotify_ListHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) TopTracks(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopResponse)
+	err := c.cc.Invoke(ctx, Spotify_TopTracks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) TopArtists(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopResponse)
+	err := c.cc.Invoke(ctx, Spotify_TopArtists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) TopAlbums(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopResponse)
+	err := c.cc.Invoke(ctx, Spotify_TopAlbums_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) ListeningTime(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*ListeningTimeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListeningTimeResponse)
+	err := c.cc.Invoke(ctx, Spotify_ListeningTime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetTopArtists(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*ArtistsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArtistsResponse)
+	err := c.cc.Invoke(ctx, Spotify_GetTopArtists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetTopTracks(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*TracksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TracksResponse)
+	err := c.cc.Invoke(ctx, Spotify_GetTopTracks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetSavedTracks(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*TracksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TracksResponse)
+	err := c.cc.Invoke(ctx, Spotify_GetSavedTracks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetPlaylists(ctx context.Context, in *LibraryRequest, opts ...grpc.CallOption) (*PlaylistsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlaylistsResponse)
+	err := c.cc.Invoke(ctx, Spotify_GetPlaylists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Play(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Play_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Pause(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Pause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Next(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Next_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Previous(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Previous_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Seek(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Seek_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Volume(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Volume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Shuffle(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Shuffle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) Repeat(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_Repeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) AddToQueue(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*State, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(State)
+	err := c.cc.Invoke(ctx, Spotify_AddToQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetQueue(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Queue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Queue)
+	err := c.cc.Invoke(ctx, Spotify_GetQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spotifyClient) GetWebhooks(ctx context.Context, in *Request, opts ...grpc.CallOption) (*WebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhooksResponse)
+	err := c.cc.Invoke(ctx, Spotify_GetWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SpotifyServer is the server API for Spotify service.
 // All implementations must embed UnimplementedSpotifyServer
 // for forward compatibility.
 type SpotifyServer interface {
-	GetTrack(context.Context, *Request) (*Track, error)
+	GetTrack(context.Context, *Request) (*State, error)
 	OnListen(*Request, grpc.ServerStreamingServer[Reponse]) error
+	SetDemand(context.Context, *Demand) (*Demand, error)
+	ListHistory(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	TopTracks(context.Context, *StatsRequest) (*TopResponse, error)
+	TopArtists(context.Context, *StatsRequest) (*TopResponse, error)
+	TopAlbums(context.Context, *StatsRequest) (*TopResponse, error)
+	ListeningTime(context.Context, *StatsRequest) (*ListeningTimeResponse, error)
+	GetTopArtists(context.Context, *LibraryRequest) (*ArtistsResponse, error)
+	GetTopTracks(context.Context, *LibraryRequest) (*TracksResponse, error)
+	GetSavedTracks(context.Context, *LibraryRequest) (*TracksResponse, error)
+	GetPlaylists(context.Context, *LibraryRequest) (*PlaylistsResponse, error)
+	Play(context.Context, *PlayerRequest) (*State, error)
+	Pause(context.Context, *PlayerRequest) (*State, error)
+	Next(context.Context, *PlayerRequest) (*State, error)
+	Previous(context.Context, *PlayerRequest) (*State, error)
+	Seek(context.Context, *PlayerRequest) (*State, error)
+	Volume(context.Context, *PlayerRequest) (*State, error)
+	Shuffle(context.Context, *PlayerRequest) (*State, error)
+	Repeat(context.Context, *PlayerRequest) (*State, error)
+	AddToQueue(context.Context, *PlayerRequest) (*State, error)
+	GetQueue(context.Context, *Request) (*Queue, error)
+	GetWebhooks(context.Context, *Request) (*WebhooksResponse, error)
 	mustEmbedUnimplementedSpotifyServer()
 }
 
@@ -84,12 +357,75 @@ type SpotifyServer interface {
 // pointer dereference when methods are called.
 type UnimplementedSpotifyServer struct{}
 
-func (UnimplementedSpotifyServer) GetTrack(context.Context, *Request) (*Track, error) {
+func (UnimplementedSpotifyServer) GetTrack(context.Context, *Request) (*State, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetTrack not implemented")
 }
 func (UnimplementedSpotifyServer) OnListen(*Request, grpc.ServerStreamingServer[Reponse]) error {
 	return status.Error(codes.Unimplemented, "method OnListen not implemented")
 }
+func (UnimplementedSpotifyServer) SetDemand(context.Context, *Demand) (*Demand, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetDemand not implemented")
+}
+func (UnimplementedSpotifyServer) ListHistory(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListHistory not implemented")
+}
+func (UnimplementedSpotifyServer) TopTracks(context.Context, *StatsRequest) (*TopResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TopTracks not implemented")
+}
+func (UnimplementedSpotifyServer) TopArtists(context.Context, *StatsRequest) (*TopResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TopArtists not implemented")
+}
+func (UnimplementedSpotifyServer) TopAlbums(context.Context, *StatsRequest) (*TopResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TopAlbums not implemented")
+}
+func (UnimplementedSpotifyServer) ListeningTime(context.Context, *StatsRequest) (*ListeningTimeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListeningTime not implemented")
+}
+func (UnimplementedSpotifyServer) GetTopArtists(context.Context, *LibraryRequest) (*ArtistsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTopArtists not implemented")
+}
+func (UnimplementedSpotifyServer) GetTopTracks(context.Context, *LibraryRequest) (*TracksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTopTracks not implemented")
+}
+func (UnimplementedSpotifyServer) GetSavedTracks(context.Context, *LibraryRequest) (*TracksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSavedTracks not implemented")
+}
+func (UnimplementedSpotifyServer) GetPlaylists(context.Context, *LibraryRequest) (*PlaylistsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPlaylists not implemented")
+}
+func (UnimplementedSpotifyServer) Play(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedSpotifyServer) Pause(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedSpotifyServer) Next(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Next not implemented")
+}
+func (UnimplementedSpotifyServer) Previous(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Previous not implemented")
+}
+func (UnimplementedSpotifyServer) Seek(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Seek not implemented")
+}
+func (UnimplementedSpotifyServer) Volume(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Volume not implemented")
+}
+func (UnimplementedSpotifyServer) Shuffle(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Shuffle not implemented")
+}
+func (UnimplementedSpotifyServer) Repeat(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method Repeat not implemented")
+}
+func (UnimplementedSpotifyServer) AddToQueue(context.Context, *PlayerRequest) (*State, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddToQueue not implemented")
+}
+func (UnimplementedSpotifyServer) GetQueue(context.Context, *Request) (*Queue, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetQueue not implemented")
+}
+func (UnimplementedSpotifyServer) GetWebhooks(context.Context, *Request) (*WebhooksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetWebhooks not implemented")
+}
 func (UnimplementedSpotifyServer) mustEmbedUnimplementedSpotifyServer() {}
 func (UnimplementedSpotifyServer) testEmbeddedByValue()                 {}
 
@@ -140,6 +476,384 @@ func _Spotify_OnListen_Handler(srv interface{}, stream grpc.ServerStream) error
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Spotify_OnListenServer = grpc.ServerStreamingServer[Reponse]
 
+func _Spotify_SetDemand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Demand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).SetDemand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_SetDemand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).SetDemand(ctx, req.(*Demand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_ListHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).ListHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_ListHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).ListHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_TopTracks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).TopTracks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_TopTracks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).TopTracks(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_TopArtists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).TopArtists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_TopArtists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).TopArtists(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_TopAlbums_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).TopAlbums(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_TopAlbums_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).TopAlbums(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_ListeningTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).ListeningTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_ListeningTime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).ListeningTime(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetTopArtists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LibraryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetTopArtists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetTopArtists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetTopArtists(ctx, req.(*LibraryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetTopTracks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LibraryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetTopTracks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetTopTracks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetTopTracks(ctx, req.(*LibraryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetSavedTracks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LibraryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetSavedTracks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetSavedTracks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetSavedTracks(ctx, req.(*LibraryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetPlaylists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LibraryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetPlaylists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetPlaylists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetPlaylists(ctx, req.(*LibraryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Play_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Play(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Play_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Play(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Pause(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Next_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Next(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Next_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Next(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Previous_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Previous(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Previous_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Previous(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Seek_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Seek(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Seek_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Seek(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Volume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Volume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Volume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Volume(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Shuffle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Shuffle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Shuffle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Shuffle(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_Repeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).Repeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_Repeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).Repeat(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_AddToQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).AddToQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_AddToQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).AddToQueue(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetQueue(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Spotify_GetWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpotifyServer).GetWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Spotify_GetWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpotifyServer).GetWebhooks(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Spotify_ServiceDesc is the grpc.ServiceDesc for Spotify service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +865,90 @@ var Spotify_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTrack",
 			Handler:    _Spotify_GetTrack_Handler,
 		},
+		{
+			MethodName: "SetDemand",
+			Handler:    _Spotify_SetDemand_Handler,
+		},
+		{
+			MethodName: "ListHistory",
+			Handler:    _Spotify_ListHistory_Handler,
+		},
+		{
+			MethodName: "TopTracks",
+			Handler:    _Spotify_TopTracks_Handler,
+		},
+		{
+			MethodName: "TopArtists",
+			Handler:    _Spotify_TopArtists_Handler,
+		},
+		{
+			MethodName: "TopAlbums",
+			Handler:    _Spotify_TopAlbums_Handler,
+		},
+		{
+			MethodName: "ListeningTime",
+			Handler:    _Spotify_ListeningTime_Handler,
+		},
+		{
+			MethodName: "GetTopArtists",
+			Handler:    _Spotify_GetTopArtists_Handler,
+		},
+		{
+			MethodName: "GetTopTracks",
+			Handler:    _Spotify_GetTopTracks_Handler,
+		},
+		{
+			MethodName: "GetSavedTracks",
+			Handler:    _Spotify_GetSavedTracks_Handler,
+		},
+		{
+			MethodName: "GetPlaylists",
+			Handler:    _Spotify_GetPlaylists_Handler,
+		},
+		{
+			MethodName: "Play",
+			Handler:    _Spotify_Play_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _Spotify_Pause_Handler,
+		},
+		{
+			MethodName: "Next",
+			Handler:    _Spotify_Next_Handler,
+		},
+		{
+			MethodName: "Previous",
+			Handler:    _Spotify_Previous_Handler,
+		},
+		{
+			MethodName: "Seek",
+			Handler:    _Spotify_Seek_Handler,
+		},
+		{
+			MethodName: "Volume",
+			Handler:    _Spotify_Volume_Handler,
+		},
+		{
+			MethodName: "Shuffle",
+			Handler:    _Spotify_Shuffle_Handler,
+		},
+		{
+			MethodName: "Repeat",
+			Handler:    _Spotify_Repeat_Handler,
+		},
+		{
+			MethodName: "AddToQueue",
+			Handler:    _Spotify_AddToQueue_Handler,
+		},
+		{
+			MethodName: "GetQueue",
+			Handler:    _Spotify_GetQueue_Handler,
+		},
+		{
+			MethodName: "GetWebhooks",
+			Handler:    _Spotify_GetWebhooks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
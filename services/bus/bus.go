@@ -0,0 +1,53 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/knadh/koanf/v2"
+	"go.uber.org/fx"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// EventBus carries the events between the processes of the gateway, so a
+// single one reads the processor stream and every one feeds its sockets
+type EventBus interface {
+	// Publish sends the data to every subscriber of the topic, including the
+	// ones of this process
+	Publish(ctx context.Context, topic string, data []byte) error
+	// Subscribe calls handler with the data of every publish to the topic, in
+	// order, until unsubscribe is called
+	Subscribe(topic string, handler func([]byte)) (unsubscribe func(), err error)
+	// Elect blocks until this process is the only holder of the name or ctx is
+	// done. Leadership lasts until ctx is done, lost is closed once it ends.
+	Elect(ctx context.Context, name string) (lost <-chan struct{}, err error)
+	Close() error
+}
+
+// New returns the bus of bus.driver, the in-memory one by default
+func New(lc fx.Lifecycle, k *koanf.Koanf) (EventBus, error) {
+	var bus EventBus
+	switch driver := k.String("bus.driver"); driver {
+	case "", DriverMemory:
+		bus = NewMemory()
+	case DriverRedis:
+		redis, err := NewRedis(k)
+		if err != nil {
+			return nil, err
+		}
+		bus = redis
+	default:
+		return nil, fmt.Errorf("bus: unknown driver %q", driver)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			return bus.Close()
+		},
+	})
+	return bus, nil
+}
@@ -0,0 +1,88 @@
+package bus
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is a bus within the process, for a single gateway without prefork
+type Memory struct {
+	mu       sync.RWMutex
+	handlers map[string]map[uint64]func([]byte)
+	seq      uint64
+
+	leadersMu sync.Mutex
+	leaders   map[string]chan struct{}
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		handlers: make(map[string]map[uint64]func([]byte)),
+		leaders:  make(map[string]chan struct{}),
+	}
+}
+
+// Publish calls the handlers right away, from the publishing goroutine
+func (m *Memory) Publish(_ context.Context, topic string, data []byte) error {
+	m.mu.RLock()
+	handlers := make([]func([]byte), 0, len(m.handlers[topic]))
+	for _, handler := range m.handlers[topic] {
+		handlers = append(handlers, handler)
+	}
+	m.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(data)
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.handlers[topic] == nil {
+		m.handlers[topic] = make(map[uint64]func([]byte))
+	}
+	m.seq++
+	id := m.seq
+	m.handlers[topic][id] = handler
+
+	return func() {
+		m.mu.Lock()
+		delete(m.handlers[topic], id)
+		m.mu.Unlock()
+	}, nil
+}
+
+func (m *Memory) Elect(ctx context.Context, name string) (<-chan struct{}, error) {
+	for {
+		m.leadersMu.Lock()
+		held, ok := m.leaders[name]
+		if !ok {
+			lost := make(chan struct{})
+			m.leaders[name] = lost
+			m.leadersMu.Unlock()
+
+			go func() {
+				<-ctx.Done()
+				m.leadersMu.Lock()
+				delete(m.leaders, name)
+				m.leadersMu.Unlock()
+				close(lost)
+			}()
+			return lost, nil
+		}
+		m.leadersMu.Unlock()
+
+		// wait for the leader to step down
+		select {
+		case <-held:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,145 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultRedisURL    = "redis://localhost:6379/0"
+	DefaultRedisPrefix = "spotify"
+	// Time a leader is kept without renewing its leadership
+	DefaultLeaderTTL = 10 * time.Second
+)
+
+var (
+	// renew extends the leadership if still held by this process
+	renew = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) end return 0`)
+	// resign releases the leadership if still held by this process
+	resign = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+)
+
+// Redis is a bus shared through Redis Pub/Sub, for preforked processes and
+// gateway replicas. Leaders hold a key renewed while alive.
+type Redis struct {
+	client *redis.Client
+	id     string
+
+	// Prefix of the channels and keys
+	Prefix string
+	// LeaderTTL is the time a leader is kept after it stopped renewing
+	LeaderTTL time.Duration
+}
+
+func NewRedis(k *koanf.Koanf) (*Redis, error) {
+	url := k.String("bus.redis.url")
+	if url == "" {
+		url = DefaultRedisURL
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("bus: invalid bus.redis.url: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	r := &Redis{
+		client:    redis.NewClient(opts),
+		id:        fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		Prefix:    k.String("bus.redis.prefix"),
+		LeaderTTL: DefaultLeaderTTL,
+	}
+	if r.Prefix == "" {
+		r.Prefix = DefaultRedisPrefix
+	}
+	if k.Exists("bus.redis.leader_ttl") {
+		r.LeaderTTL = k.Duration("bus.redis.leader_ttl")
+	}
+	return r, nil
+}
+
+func (r *Redis) Publish(ctx context.Context, topic string, data []byte) error {
+	return r.client.Publish(ctx, r.key(topic), data).Err()
+}
+
+func (r *Redis) Subscribe(topic string, handler func([]byte)) (func(), error) {
+	sub := r.client.Subscribe(context.Background(), r.key(topic))
+	// wait for the confirmation, so no publish after Subscribe is missed
+	if _, err := sub.Receive(context.Background()); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	// the channel resubscribes on its own after connection errors
+	go func() {
+		for msg := range sub.Channel() {
+			handler([]byte(msg.Payload))
+		}
+	}()
+	return func() { sub.Close() }, nil
+}
+
+func (r *Redis) Elect(ctx context.Context, name string) (<-chan struct{}, error) {
+	key := r.key("leader:" + name)
+	for {
+		held, err := r.client.SetNX(ctx, key, r.id, r.LeaderTTL).Result()
+		if err != nil && ctx.Err() == nil {
+			log.Printf("error while electing the %s leader: %v", name, err)
+		}
+		if held {
+			lost := make(chan struct{})
+			go r.lead(ctx, key, lost)
+			return lost, nil
+		}
+
+		select {
+		case <-time.After(r.LeaderTTL / 3):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// lead renews the leadership until ctx is done or it can't be renewed, a
+// leader unable to reach Redis steps down before another one is elected
+func (r *Redis) lead(ctx context.Context, key string, lost chan struct{}) {
+	defer close(lost)
+	ticker := time.NewTicker(r.LeaderTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			resignCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			resign.Run(resignCtx, r.client, []string{key}, r.id)
+			cancel()
+			return
+		case <-ticker.C:
+			renewed, err := renew.Run(ctx, r.client, []string{key}, r.id, r.LeaderTTL.Milliseconds()).Int()
+			if errors.Is(err, context.Canceled) {
+				// resigns on the next loop
+				continue
+			}
+			if err != nil {
+				log.Printf("error while renewing the leadership of %s: %v", key, err)
+			}
+			if err != nil || renewed == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+func (r *Redis) key(name string) string {
+	return r.Prefix + ":" + name
+}
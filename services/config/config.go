@@ -0,0 +1,292 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"spotify/utils"
+
+	"github.com/knadh/koanf/parsers/toml"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// DefaultPath is the file read when neither -config nor SPOTIFY_CONFIG are
+	// given, it may be missing
+	DefaultPath = "config.toml"
+	// EnvPrefix prefixes the environment variables overriding the file
+	EnvPrefix = "SPOTIFY_"
+)
+
+// SpotifyCredentials are required by the binaries calling Spotify, see Require
+var SpotifyCredentials = []string{"spotify.client_id", "spotify.client_secret", "spotify.refresh_token"}
+
+// Aliases are older keys still accepted, the value goes to the current key
+// unless it is set too
+var Aliases = map[string]string{
+	"socket.origins":           "websocket.origins",
+	"socket.read_buffer_size":  "websocket.read_buffer_size",
+	"socket.write_buffer_size": "websocket.write_buffer_size",
+}
+
+// defaults of the keys without default in their package
+var defaults = map[string]any{
+	"server.port":    5000,
+	"grpc.host":      "localhost",
+	"grpc.port":      5001,
+	"processor.mode": "remote",
+	"bus.driver":     "memory",
+}
+
+// Config is the typed configuration shared by every binary, each one reads
+// the sections it needs
+type Config struct {
+	Server struct {
+		Host     string `koanf:"host"`
+		Port     int    `koanf:"port"`
+		Prefork  bool   `koanf:"prefork"`
+		Timezone string `koanf:"timezone"`
+	} `koanf:"server"`
+	GRPC struct {
+		Host  string `koanf:"host"`
+		Port  int    `koanf:"port"`
+		Token string `koanf:"token"`
+		TLS   struct {
+			Enabled    bool   `koanf:"enabled"`
+			CA         string `koanf:"ca"`
+			Cert       string `koanf:"cert"`
+			Key        string `koanf:"key"`
+			ServerName string `koanf:"server_name"`
+		} `koanf:"tls"`
+	} `koanf:"grpc"`
+	Processor struct {
+		Mode string `koanf:"mode"`
+	} `koanf:"processor"`
+	Websocket struct {
+		Origins         []string `koanf:"origins"`
+		ReadBufferSize  int      `koanf:"read_buffer_size"`
+		WriteBufferSize int      `koanf:"write_buffer_size"`
+	} `koanf:"websocket"`
+	Bus struct {
+		Driver string `koanf:"driver"`
+		Redis  struct {
+			URL       string        `koanf:"url"`
+			Prefix    string        `koanf:"prefix"`
+			LeaderTTL time.Duration `koanf:"leader_ttl"`
+		} `koanf:"redis"`
+	} `koanf:"bus"`
+	Spotify struct {
+		ClientID         string `koanf:"client_id"`
+		ClientSecret     string `koanf:"client_secret"`
+		RefreshToken     string `koanf:"refresh_token"`
+		IdlePollRate     int    `koanf:"idle_poll_rate"`
+		QueuePollRate    int    `koanf:"queue_poll_rate"`
+		FallbackImageURL string `koanf:"fallback_image_url"`
+		Artists          struct {
+			Budget    time.Duration `koanf:"budget"`
+			CacheSize int           `koanf:"cache_size"`
+			CacheTTL  time.Duration `koanf:"cache_ttl"`
+			CacheFile string        `koanf:"cache_file"`
+		} `koanf:"artists"`
+		Library struct {
+			CacheTTL time.Duration `koanf:"cache_ttl"`
+		} `koanf:"library"`
+	} `koanf:"spotify"`
+	Admin struct {
+		APIKeys []string `koanf:"api_keys"`
+	} `koanf:"admin"`
+	History struct {
+		Path      string        `koanf:"path"`
+		Threshold time.Duration `koanf:"threshold"`
+	} `koanf:"history"`
+	Stats struct {
+		Timezone string `koanf:"timezone"`
+	} `koanf:"stats"`
+	Webhook struct {
+		MaxAttempts int             `koanf:"max_attempts"`
+		Backoff     time.Duration   `koanf:"backoff"`
+		Timeout     time.Duration   `koanf:"timeout"`
+		DeadLetter  string          `koanf:"dead_letter"`
+		Endpoints   []WebhookTarget `koanf:"endpoints"`
+	} `koanf:"webhook"`
+	MQTT struct {
+		Broker      string `koanf:"broker"`
+		ClientID    string `koanf:"client_id"`
+		Username    string `koanf:"username"`
+		Password    string `koanf:"password"`
+		User        string `koanf:"user"`
+		TopicPrefix string `koanf:"topic_prefix"`
+		Topics      struct {
+			State        string `koanf:"state"`
+			Track        string `koanf:"track"`
+			Playing      string `koanf:"playing"`
+			Availability string `koanf:"availability"`
+		} `koanf:"topics"`
+		QoS       int  `koanf:"qos"`
+		Retain    bool `koanf:"retain"`
+		Discovery struct {
+			Enabled bool   `koanf:"enabled"`
+			Prefix  string `koanf:"prefix"`
+		} `koanf:"discovery"`
+	} `koanf:"mqtt"`
+	Scrobble struct {
+		Queue         string        `koanf:"queue"`
+		RetryInterval time.Duration `koanf:"retry_interval"`
+		LastFM        struct {
+			APIKey     string `koanf:"api_key"`
+			APISecret  string `koanf:"api_secret"`
+			SessionKey string `koanf:"session_key"`
+			Username   string `koanf:"username"`
+			Password   string `koanf:"password"`
+			URL        string `koanf:"url"`
+		} `koanf:"lastfm"`
+		ListenBrainz struct {
+			Token string `koanf:"token"`
+			URL   string `koanf:"url"`
+		} `koanf:"listenbrainz"`
+	} `koanf:"scrobble"`
+	Metrics struct {
+		Port int `koanf:"port"`
+	} `koanf:"metrics"`
+	Tracing struct {
+		Endpoint    string  `koanf:"endpoint"`
+		Insecure    bool    `koanf:"insecure"`
+		SampleRatio float64 `koanf:"sample_ratio"`
+		ServiceName string  `koanf:"service_name"`
+	} `koanf:"tracing"`
+
+	// Args left after the flags, eg: a subcommand
+	Args []string `koanf:"-"`
+
+	// path of the file loaded, empty without file
+	path string
+	// -set flags, applied again on reload
+	sets []string
+}
+
+// WebhookTarget is an endpoint of webhook.endpoints
+type WebhookTarget struct {
+	URL    string   `koanf:"url"`
+	Secret string   `koanf:"secret"`
+	Events []string `koanf:"events"`
+}
+
+// Load layers the configuration, each layer overriding the previous ones:
+//   - the defaults
+//   - the TOML file of -config, SPOTIFY_CONFIG or config.toml, with #{VAR}
+//     replaced by the environment variable VAR
+//   - the environment variables SPOTIFY_<KEY>, and SPOTIFY_<KEY>_FILE read
+//     from a file, eg: SPOTIFY_GRPC_TOKEN_FILE=/run/secrets/token
+//   - the flags -set key=value
+//
+// The result is validated, the binaries check the keys they require with
+// Require.
+func Load(args []string) (*koanf.Koanf, *Config, error) {
+	var (
+		path string
+		sets []string
+	)
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.StringVar(&path, "config", "", "TOML file of the configuration (default $SPOTIFY_CONFIG or "+DefaultPath+")")
+	fs.Func("set", "Set a key, eg: -set server.port=8080 (repeatable)", func(value string) error {
+		sets = append(sets, value)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	k, cfg, err := load(path, sets)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.Args = fs.Args()
+	return k, cfg, nil
+}
+
+// load layers the configuration of the file at path, resolved by loadFile
+// when empty, and the -set flags
+func load(path string, sets []string) (*koanf.Koanf, *Config, error) {
+	k := koanf.New(".")
+	if err := k.Load(confmap.Provider(defaults, "."), nil); err != nil {
+		return nil, nil, err
+	}
+	path, err := loadFile(k, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := loadEnv(k, os.Environ()); err != nil {
+		return nil, nil, err
+	}
+	if err := loadSets(k, sets); err != nil {
+		return nil, nil, err
+	}
+	resolveAliases(k)
+
+	cfg := &Config{}
+	if err := k.Unmarshal("", cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfg.path, cfg.sets = path, sets
+	if err := cfg.validate(k); err != nil {
+		return nil, nil, err
+	}
+	return k, cfg, nil
+}
+
+// loadFile loads the TOML file and returns its path, a missing default file is
+// skipped so the environment alone can configure a container
+func loadFile(k *koanf.Koanf, path string) (string, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if path == "" {
+		path, explicit = DefaultPath, false
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
+	}
+	values, err := toml.Parser().Unmarshal(utils.ReplaceValues(data))
+	if err != nil {
+		return "", fmt.Errorf("config: invalid %s: %w", path, err)
+	}
+	return path, k.Load(confmap.Provider(values, ""), nil)
+}
+
+// loadSets loads the -set flags, only known keys are accepted
+func loadSets(k *koanf.Koanf, sets []string) error {
+	keys := Keys()
+	values := map[string]any{}
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("config: invalid -set %q, expected key=value", set)
+		}
+		kind, known := keys[key]
+		if !known {
+			return fmt.Errorf("config: unknown key %q in -set", key)
+		}
+		values[key] = parse(kind, value)
+	}
+	return k.Load(confmap.Provider(values, "."), nil)
+}
+
+// resolveAliases moves the values of the aliases to their current key
+func resolveAliases(k *koanf.Koanf) {
+	for alias, key := range Aliases {
+		if k.Exists(alias) && !k.Exists(key) {
+			k.Set(key, k.Get(alias))
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// loadConfig loads the file (skipped when empty) as config.toml of a new
+// directory with only the variables of env
+func loadConfig(t *testing.T, file string, env map[string]string, sets ...string) (*koanf.Koanf, error) {
+	t.Helper()
+	for _, entry := range os.Environ() {
+		if name, _, _ := strings.Cut(entry, "="); strings.HasPrefix(name, EnvPrefix) {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	t.Chdir(t.TempDir())
+	if file != "" {
+		if err := os.WriteFile(DefaultPath, []byte(file), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	k, _, err := load("", sets)
+	return k, err
+}
+
+func TestLoadLayers(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		sets []string
+		key  string
+		want any
+	}{
+		{"default", "", nil, nil, "server.port", 5000},
+		{"file over default", "[server]\nport = 6000", nil, nil, "server.port", 6000},
+		{"env over file", "[server]\nport = 6000", map[string]string{"SPOTIFY_SERVER_PORT": "7000"}, nil, "server.port", 7000},
+		{"set over env", "[server]\nport = 6000", map[string]string{"SPOTIFY_SERVER_PORT": "7000"}, []string{"server.port=8000"}, "server.port", 8000},
+		{"file variable", "[grpc]\ntoken = \"#{TEST_TOKEN}\"", map[string]string{"TEST_TOKEN": "replaced"}, nil, "grpc.token", "replaced"},
+		{"secret file", "", map[string]string{"SPOTIFY_GRPC_TOKEN_FILE": secret}, nil, "grpc.token", "from-file"},
+		{"short spotify name", "", map[string]string{"SPOTIFY_CLIENT_ID": "short"}, nil, "spotify.client_id", "short"},
+		{"full name over short", "", map[string]string{"SPOTIFY_CLIENT_ID": "short", "SPOTIFY_SPOTIFY_CLIENT_ID": "full"}, nil, "spotify.client_id", "full"},
+		{"full name over short secret file", "", map[string]string{"SPOTIFY_CLIENT_ID_FILE": secret, "SPOTIFY_SPOTIFY_CLIENT_ID": "full"}, nil, "spotify.client_id", "full"},
+		{"list from env", "", map[string]string{"SPOTIFY_WEBSOCKET_ORIGINS": "https://a.com, https://b.com,"}, nil, "websocket.origins", []string{"https://a.com", "https://b.com"}},
+		{"list from set", "", nil, []string{"admin.api_keys=one,two"}, "admin.api_keys", []string{"one", "two"}},
+		{"alias", "[socket]\norigins = [\"https://old.com\"]", nil, nil, "websocket.origins", []string{"https://old.com"}},
+		{"alias from env", "", map[string]string{"SPOTIFY_SOCKET_READ_BUFFER_SIZE": "2048"}, nil, "websocket.read_buffer_size", 2048},
+		{"current key over alias", "[socket]\norigins = [\"https://old.com\"]\n[websocket]\norigins = [\"https://new.com\"]", nil, nil, "websocket.origins", []string{"https://new.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := loadConfig(t, tt.file, tt.env, tt.sets...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got any
+			switch tt.want.(type) {
+			case int:
+				got = k.Int(tt.key)
+			case []string:
+				got = k.Strings(tt.key)
+			default:
+				got = k.String(tt.key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("%s is %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		sets []string
+		want string
+	}{
+		{"same variable twice", "", map[string]string{"SPOTIFY_CLIENT_ID": "a", "SPOTIFY_CLIENT_ID_FILE": "/dev/null"}, nil, "both set spotify.client_id"},
+		{"missing secret file", "", map[string]string{"SPOTIFY_GRPC_TOKEN_FILE": "/does/not/exist"}, nil, "SPOTIFY_GRPC_TOKEN_FILE"},
+		{"unknown set", "", nil, []string{"server.nope=1"}, `unknown key "server.nope"`},
+		{"set without value", "", nil, []string{"server.port"}, "expected key=value"},
+		{"invalid file", "[server\nport = 1", nil, nil, "invalid config.toml"},
+		{"invalid value", "[server]\nport = \"many\"", nil, nil, "invalid configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(t, tt.file, tt.env, tt.sets...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want an error with %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadExplicitFile(t *testing.T) {
+	if _, err := loadConfig(t, "", map[string]string{"SPOTIFY_CONFIG": "missing.toml"}); err == nil {
+		t.Fatal("a missing SPOTIFY_CONFIG was skipped")
+	}
+}
+
+func TestLoadFlags(t *testing.T) {
+	// without variables nor config.toml
+	if _, err := loadConfig(t, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "other.toml")
+	if err := os.WriteFile(path, []byte("[grpc]\nport = 6001"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, cfg, err := Load([]string{"-config", path, "-set", "server.port=6000", "import", "file.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Int("grpc.port") != 6001 || k.Int("server.port") != 6000 || !reflect.DeepEqual(cfg.Args, []string{"import", "file.json"}) {
+		t.Fatalf("got grpc.port %d, server.port %d and the args %v", k.Int("grpc.port"), k.Int("server.port"), cfg.Args)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"port out of range", "[server]\nport = 70000", "server.port"},
+		{"negative metrics port", "[metrics]\nport = -1", "metrics.port"},
+		{"empty grpc host", "[grpc]\nhost = \"\"", "grpc.host"},
+		{"unknown processor mode", "[processor]\nmode = \"local\"", "processor.mode"},
+		{"embedded with prefork", "[processor]\nmode = \"embedded\"\n[server]\nprefork = true\n[bus]\ndriver = \"redis\"", "processor.mode"},
+		{"memory bus with prefork", "[server]\nprefork = true", "bus.driver"},
+		{"unknown bus", "[bus]\ndriver = \"nats\"", "bus.driver"},
+		{"unknown time zone", "[stats]\ntimezone = \"Mars/Olympus\"", "stats.timezone"},
+		{"cert without key", "[grpc.tls]\ncert = \"cert.pem\"", "grpc.tls"},
+		{"key without cert", "[grpc.tls]\nkey = \"key.pem\"", "grpc.tls"},
+		{"negative buffer", "[websocket]\nread_buffer_size = -1", "websocket.read_buffer_size"},
+		{"zero idle poll rate", "[spotify]\nidle_poll_rate = 0", "spotify.idle_poll_rate"},
+		{"negative idle poll rate", "[spotify]\nidle_poll_rate = -5", "spotify.idle_poll_rate"},
+		{"zero queue poll rate", "[spotify]\nqueue_poll_rate = 0", "spotify.queue_poll_rate"},
+		{"zero cache ttl", "[spotify.artists]\ncache_ttl = \"0s\"", "spotify.artists.cache_ttl"},
+		{"negative budget", "[spotify.artists]\nbudget = \"-1s\"", "spotify.artists.budget"},
+		{"negative threshold", "[history]\nthreshold = \"-30s\"", "history.threshold"},
+		{"zero webhook attempts", "[webhook]\nmax_attempts = 0", "webhook.max_attempts"},
+		{"webhook url", "[[webhook.endpoints]]\nurl = \"ftp://example.com\"", "webhook.endpoints[0].url"},
+		{"mqtt qos", "[mqtt]\nqos = 3", "mqtt.qos"},
+		{"sample ratio", "[tracing]\nsample_ratio = 1.5", "tracing.sample_ratio"},
+		{"lastfm without secret", "[scrobble.lastfm]\napi_key = \"key\"\nsession_key = \"session\"", "scrobble.lastfm.api_secret"},
+		{"lastfm without session", "[scrobble.lastfm]\napi_key = \"key\"\napi_secret = \"secret\"", "scrobble.lastfm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(t, tt.file, nil)
+			if err == nil || !strings.Contains(err.Error(), "  "+tt.want+": ") {
+				t.Fatalf("got %v, want an error of %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	_, err := loadConfig(t, "[server]\nport = 0\n[grpc]\nport = 0\n[spotify]\nidle_poll_rate = 0", nil)
+	if err == nil || strings.Count(err.Error(), "\n") != 3 {
+		t.Fatalf("got %v, want the 3 errors", err)
+	}
+}
+
+func TestValidConfig(t *testing.T) {
+	file := `
+[server]
+port = 8080
+prefork = true
+timezone = "Europe/Berlin"
+
+[bus]
+driver = "redis"
+
+[grpc.tls]
+cert = "cert.pem"
+key = "key.pem"
+
+[spotify]
+idle_poll_rate = 30
+
+[[webhook.endpoints]]
+url = "https://example.com/hook"
+`
+	if _, err := loadConfig(t, file, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	k, err := loadConfig(t, "", map[string]string{"SPOTIFY_CLIENT_ID": "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Require(k, SpotifyCredentials...)
+	if err == nil || strings.Contains(err.Error(), "client_id") || !strings.Contains(err.Error(), "SPOTIFY_CLIENT_SECRET") || !strings.Contains(err.Error(), "SPOTIFY_REFRESH_TOKEN") {
+		t.Fatalf("got %v, want the secret and the refresh token required", err)
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+// fileSuffix marks the variables holding the path of a file with the value,
+// eg: Docker secrets
+const fileSuffix = "_FILE"
+
+// Keys returns every key of Config and the aliases, with the kind of their
+// value. Lists of tables, like webhook.endpoints, only come from the file.
+func Keys() map[string]reflect.Kind {
+	keys := map[string]reflect.Kind{}
+	var walk func(prefix string, t reflect.Type)
+	walk = func(prefix string, t reflect.Type) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			tag := field.Tag.Get("koanf")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			switch kind := field.Type.Kind(); {
+			case kind == reflect.Struct && field.Type.String() != "time.Duration":
+				walk(prefix+tag+".", field.Type)
+			case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			default:
+				keys[prefix+tag] = kind
+			}
+		}
+	}
+	walk("", reflect.TypeFor[Config]())
+
+	for alias, key := range Aliases {
+		keys[alias] = keys[key]
+	}
+	return keys
+}
+
+// EnvNames returns the environment variables of every key, eg:
+// SPOTIFY_SERVER_PORT for server.port. The keys of the spotify section can
+// drop it, eg: SPOTIFY_CLIENT_ID for spotify.client_id.
+func EnvNames() map[string]string {
+	names := map[string]string{}
+	for key := range Keys() {
+		if rest, ok := strings.CutPrefix(key, "spotify."); ok {
+			names[envName(rest)] = key
+		}
+	}
+	// the full names take precedence over the short ones
+	for key := range Keys() {
+		names[envName(key)] = key
+	}
+	return names
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// loadEnv loads the SPOTIFY_ variables of environ, reading the _FILE ones from
+// their file. Unknown variables are reported, as they are likely typos.
+func loadEnv(k *koanf.Koanf, environ []string) error {
+	type source struct {
+		name  string
+		full  bool
+		value any
+	}
+	names, keys := EnvNames(), Keys()
+	sources := map[string]source{}
+
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || name == EnvPrefix+"CONFIG" {
+			continue
+		}
+
+		base := name
+		key, known := names[base]
+		if !known {
+			var isFile bool
+			base, isFile = strings.CutSuffix(name, fileSuffix)
+			if key, known = names[base]; !known || !isFile {
+				log.Printf("Unknown configuration variable %s, ignored", name)
+				continue
+			}
+			data, err := os.ReadFile(value)
+			if err != nil {
+				return fmt.Errorf("config: %s: %w", name, err)
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		}
+
+		full := base == envName(key)
+		if previous, set := sources[key]; set {
+			if previous.full == full {
+				return fmt.Errorf("config: %s and %s both set %s", previous.name, name, key)
+			}
+			// the full name wins over the short one
+			if previous.full {
+				continue
+			}
+		}
+		sources[key] = source{name: name, full: full, value: parse(keys[key], value)}
+	}
+
+	values := make(map[string]any, len(sources))
+	for key, source := range sources {
+		values[key] = source.value
+	}
+	return k.Load(confmap.Provider(values, "."), nil)
+}
+
+// parse converts the text of a list to its items, separated by commas, other
+// kinds are converted by the getters and the validation
+func parse(kind reflect.Kind, value string) any {
+	if kind != reflect.Slice {
+		return value
+	}
+	items := []string{}
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
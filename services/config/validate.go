@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// validate reports every invalid value at once, so the configuration can be
+// fixed in one go
+func (c *Config) validate(k *koanf.Koanf) error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+	// positive checks the keys given, unset ones fall back to their default
+	positive := func(key string, value float64) {
+		check(!k.Exists(key) || value > 0, key, "must be positive, got %v", k.Get(key))
+	}
+
+	check(validPort(c.Server.Port), "server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	check(validPort(c.GRPC.Port), "grpc.port", "must be between 1 and 65535, got %d", c.GRPC.Port)
+	check(c.Metrics.Port == 0 || validPort(c.Metrics.Port), "metrics.port", "must be between 1 and 65535, got %d", c.Metrics.Port)
+	check(c.GRPC.Host != "", "grpc.host", "is required")
+	check(slices.Contains([]string{"remote", "embedded"}, c.Processor.Mode), "processor.mode", "must be remote or embedded, got %q", c.Processor.Mode)
+	check(c.Processor.Mode != "embedded" || !c.Server.Prefork, "processor.mode", "embedded can't be used with server.prefork")
+	check(slices.Contains([]string{"memory", "redis"}, c.Bus.Driver), "bus.driver", "must be memory or redis, got %q", c.Bus.Driver)
+	// the preforked processes would each read the processor stream
+	check(c.Bus.Driver != "memory" || !c.Server.Prefork, "bus.driver", "memory can't be used with server.prefork, use redis")
+
+	for _, key := range []string{"server.timezone", "stats.timezone"} {
+		if name := k.String(key); name != "" {
+			_, err := time.LoadLocation(name)
+			check(err == nil, key, "unknown time zone %q", name)
+		}
+	}
+
+	check((c.GRPC.TLS.Cert == "") == (c.GRPC.TLS.Key == ""), "grpc.tls", "cert and key must be given together")
+	check(c.Websocket.ReadBufferSize >= 0, "websocket.read_buffer_size", "must not be negative, got %d", c.Websocket.ReadBufferSize)
+	check(c.Websocket.WriteBufferSize >= 0, "websocket.write_buffer_size", "must not be negative, got %d", c.Websocket.WriteBufferSize)
+
+	positive("bus.redis.leader_ttl", c.Bus.Redis.LeaderTTL.Seconds())
+	positive("spotify.idle_poll_rate", float64(c.Spotify.IdlePollRate))
+	positive("spotify.queue_poll_rate", float64(c.Spotify.QueuePollRate))
+	positive("spotify.artists.cache_size", float64(c.Spotify.Artists.CacheSize))
+	positive("spotify.artists.cache_ttl", c.Spotify.Artists.CacheTTL.Seconds())
+	positive("spotify.library.cache_ttl", c.Spotify.Library.CacheTTL.Seconds())
+	positive("webhook.max_attempts", float64(c.Webhook.MaxAttempts))
+	positive("webhook.backoff", c.Webhook.Backoff.Seconds())
+	positive("webhook.timeout", c.Webhook.Timeout.Seconds())
+	positive("scrobble.retry_interval", c.Scrobble.RetryInterval.Seconds())
+	check(c.Spotify.Artists.Budget >= 0, "spotify.artists.budget", "must not be negative, got %s", c.Spotify.Artists.Budget)
+	check(c.History.Threshold >= 0, "history.threshold", "must not be negative, got %s", c.History.Threshold)
+
+	for i, endpoint := range c.Webhook.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", fmt.Sprintf("webhook.endpoints[%d].url", i), "must be an http(s) URL, got %q", endpoint.URL)
+	}
+
+	check(c.MQTT.QoS >= 0 && c.MQTT.QoS <= 2, "mqtt.qos", "must be 0, 1 or 2, got %d", c.MQTT.QoS)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	if lastfm := c.Scrobble.LastFM; lastfm.APIKey != "" {
+		check(lastfm.APISecret != "", "scrobble.lastfm.api_secret", "is required with scrobble.lastfm.api_key")
+		check(lastfm.SessionKey != "" || (lastfm.Username != "" && lastfm.Password != ""), "scrobble.lastfm", "session_key, or username and password, are required with api_key")
+	}
+
+	return joinErrors(errs)
+}
+
+// Require reports the keys left empty, for the binaries needing them
+func Require(k *koanf.Koanf, keys ...string) error {
+	var errs []error
+	for _, key := range keys {
+		if k.String(key) == "" {
+			// suggest the short variable of the spotify section
+			name := strings.TrimPrefix(key, "spotify.")
+			errs = append(errs, fmt.Errorf("%s: is required, set it in the file or as %s", key, envName(name)))
+		}
+	}
+	return joinErrors(errs)
+}
+
+// joinErrors lists the errors one per line
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = "  " + err.Error()
+	}
+	return errors.New("invalid configuration:\n" + strings.Join(lines, "\n"))
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
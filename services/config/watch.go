@@ -0,0 +1,267 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+	"go.uber.org/fx"
+)
+
+const (
+	// Wait for the writes of a save to settle before reloading
+	reloadDelay = 100 * time.Millisecond
+	// Wait before watching again a file that was replaced or removed
+	rewatchDelay = time.Second
+)
+
+// Reloadable are the keys applied when the file changes, changing the others
+// needs a restart. The aliases of these keys are reloadable too.
+var Reloadable = []string{
+	"websocket.origins",
+	"admin.api_keys",
+	"spotify.idle_poll_rate",
+	"spotify.queue_poll_rate",
+	"webhook.endpoints",
+}
+
+// secretWords mark the keys whose values aren't logged
+var secretWords = []string{"secret", "token", "password", "key"}
+
+// Watcher reloads the configuration when its file changes. Only the
+// Reloadable keys are applied, the other changes are logged and ignored.
+type Watcher struct {
+	path string
+	sets []string
+	k    atomic.Pointer[koanf.Koanf]
+	// last configuration loaded from the file, the changes needing a restart
+	// are reported against it so they are logged once
+	loaded *koanf.Koanf
+
+	mu        sync.Mutex
+	listeners []func(*koanf.Koanf)
+	provider  *file.File
+	timer     *time.Timer
+	stopped   bool
+
+	// reloads run one at a time
+	reloadMu sync.Mutex
+}
+
+// Watch watches the file the configuration was loaded from while the app
+// runs, without file there is nothing to reload
+func Watch(lc fx.Lifecycle, k *koanf.Koanf, cfg *Config) *Watcher {
+	w := &Watcher{path: cfg.path, sets: cfg.sets, loaded: k}
+	w.k.Store(k)
+	if w.path == "" {
+		return w
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			return w.watch()
+		},
+		OnStop: func(_ context.Context) error {
+			w.stop()
+			return nil
+		},
+	})
+	return w
+}
+
+// Koanf returns the configuration with the reloads applied
+func (w *Watcher) Koanf() *koanf.Koanf {
+	return w.k.Load()
+}
+
+// OnReload calls fn with the configuration after every reload applying changes
+func (w *Watcher) OnReload(fn func(k *koanf.Koanf)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+func (w *Watcher) watch() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return nil
+	}
+
+	provider := file.Provider(w.path)
+	if err := provider.Watch(w.onEvent); err != nil {
+		return fmt.Errorf("config: watching %s: %w", w.path, err)
+	}
+	w.provider = provider
+	return nil
+}
+
+func (w *Watcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	if w.provider != nil {
+		w.provider.Unwatch()
+	}
+}
+
+func (w *Watcher) onEvent(_ any, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return
+	}
+
+	if err != nil {
+		// editors saving through a new file end the watch
+		log.Printf("Stopped watching %s, watching it again: %v", w.path, err)
+		time.AfterFunc(rewatchDelay, w.rewatch)
+		return
+	}
+	w.schedule()
+}
+
+// rewatch watches the file again once it is back, it may have changed
+// meanwhile
+func (w *Watcher) rewatch() {
+	if err := w.watch(); err != nil {
+		time.AfterFunc(rewatchDelay, w.rewatch)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.schedule()
+}
+
+// schedule reloads once the events stop, it must be called with mu held
+func (w *Watcher) schedule() {
+	if w.timer == nil {
+		w.timer = time.AfterFunc(reloadDelay, w.reload)
+	} else {
+		w.timer.Reset(reloadDelay)
+	}
+}
+
+// reload applies the changes of the Reloadable keys, logging every change.
+// An invalid file is rejected as a whole.
+func (w *Watcher) reload() {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	next, _, err := load(w.path, w.sets)
+	if err != nil {
+		log.Printf("Configuration reload of %s rejected: %v", w.path, err)
+		return
+	}
+
+	current := w.Koanf()
+	applied := current.Copy()
+	var changes, ignored []string
+	for _, key := range changedKeys(current, next) {
+		before, after := current.Get(key), next.Get(key)
+		change := fmt.Sprintf("%s: %s -> %s", key, show(key, before), show(key, after))
+		if !reloadable(key) {
+			if !reflect.DeepEqual(w.loaded.Get(key), after) {
+				ignored = append(ignored, change)
+			}
+			continue
+		}
+
+		if after == nil {
+			applied.Delete(key)
+		} else {
+			applied.Set(key, after)
+		}
+		changes = append(changes, change)
+	}
+
+	w.loaded = next
+	if len(ignored) > 0 {
+		log.Printf("Configuration changes of %s need a restart, ignored: %s", w.path, strings.Join(ignored, "; "))
+	}
+	if len(changes) == 0 {
+		log.Printf("Configuration reloaded from %s: no change applied", w.path)
+		return
+	}
+	log.Printf("Configuration reloaded from %s: %s", w.path, strings.Join(changes, "; "))
+
+	w.k.Store(applied)
+	w.mu.Lock()
+	listeners := slices.Clone(w.listeners)
+	w.mu.Unlock()
+	for _, fn := range listeners {
+		fn(applied)
+	}
+}
+
+// changedKeys returns the keys whose value differs, sorted
+func changedKeys(current, next *koanf.Koanf) []string {
+	before, after := current.All(), next.All()
+	var keys []string
+	for key, value := range before {
+		if !reflect.DeepEqual(value, after[key]) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func reloadable(key string) bool {
+	if alias, ok := Aliases[key]; ok {
+		key = alias
+	}
+	return slices.Contains(Reloadable, key)
+}
+
+// show formats the value of key for the logs, hiding the secrets
+func show(key string, value any) string {
+	if value == nil {
+		return "<unset>"
+	}
+	return fmt.Sprint(redact(key, value))
+}
+
+func redact(key string, value any) any {
+	name := key[strings.LastIndex(key, ".")+1:]
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return "***"
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		// eg: the tables of webhook.endpoints
+		redacted := make(map[string]any, len(value))
+		for field, item := range value {
+			redacted[field] = redact(field, item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, item := range value {
+			redacted[i] = redact(key, item)
+		}
+		return redacted
+	}
+	return value
+}
@@ -9,15 +9,65 @@ import (
 	"github.com/knadh/koanf/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type SpotifyClient = protocols.SpotifyClient
 
-func Connect(k *koanf.Koanf) (protocols.SpotifyClient, error) {
-	conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", k.String("grpc.host"), k.Int("grpc.port")), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// HealthClient checks the grpc.health.v1 service of the processor
+type HealthClient = healthgrpc.HealthClient
+
+// HealthMethodPrefix prefixes the methods of the grpc.health.v1 service
+const HealthMethodPrefix = "/grpc.health.v1.Health/"
+
+func Connect(k *koanf.Koanf) (protocols.SpotifyClient, HealthClient, error) {
+	opts, err := DialOptions(k)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", k.String("grpc.host"), k.Int("grpc.port")), opts...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	log.Printf("Connect to GRPC server at \"%s\"", conn.CanonicalTarget())
-	return protocols.NewSpotifyClient(conn), nil
+	return protocols.NewSpotifyClient(conn), healthgrpc.NewHealthClient(conn), nil
+}
+
+// DialOptions secures the connection to the processor with grpc.tls.enabled,
+// and sends grpc.token and the trace context with every call
+func DialOptions(k *koanf.Koanf) ([]grpc.DialOption, error) {
+	secure := k.Bool("grpc.tls.enabled")
+	creds := insecure.NewCredentials()
+	if secure {
+		var err error
+		if creds, err = ClientCredentials(k); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds), grpc.WithStatsHandler(ClientTracing())}
+	if token := k.String("grpc.token"); token != "" {
+		if !secure {
+			log.Println("grpc.token is sent in clear text, enable grpc.tls to protect it")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: token, secure: secure}))
+	}
+	return opts, nil
+}
+
+// ServerOptions serves TLS with grpc.tls.enabled, requires grpc.token from
+// every call and continues the trace of the caller
+func ServerOptions(k *koanf.Koanf) ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{grpc.StatsHandler(ServerTracing())}
+	if k.Bool("grpc.tls.enabled") {
+		creds, err := ServerCredentials(k)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	if token := k.String("grpc.token"); token != "" {
+		opts = append(opts, grpc.ChainUnaryInterceptor(UnaryToken(token)), grpc.ChainStreamInterceptor(StreamToken(token)))
+	}
+	return opts, nil
 }
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"spotify/protocols"
+
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Buffer of the in-memory connection to the embedded processor
+const embeddedBufferSize = 1024 * 1024
+
+// Embed serves the processor in-process over an in-memory listener. The client
+// goes through gRPC as with a remote processor, streams and errors included.
+func Embed(lc fx.Lifecycle, processor protocols.SpotifyServer, health healthgrpc.HealthServer) (protocols.SpotifyClient, HealthClient, error) {
+	listener := bufconn.Listen(embeddedBufferSize)
+	srv := grpc.NewServer(grpc.StatsHandler(ServerTracing()))
+	protocols.RegisterSpotifyServer(srv, processor)
+	healthgrpc.RegisterHealthServer(srv, health)
+	// served right away, the gateway asks for the state while starting
+	go func() {
+		if err := srv.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///embedded",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(ClientTracing()),
+	)
+	if err != nil {
+		srv.Stop()
+		return nil, nil, err
+	}
+	log.Println("Running the processor embedded")
+
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			conn.Close()
+			srv.Stop()
+			return nil
+		},
+	})
+	return protocols.NewSpotifyClient(conn), healthgrpc.NewHealthClient(conn), nil
+}
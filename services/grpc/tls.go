@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/knadh/koanf/v2"
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientCredentials returns the TLS credentials to reach the processor. The CA
+// verifies the processor instead of the system roots, and the certificate
+// authenticates the gateway when the processor requires client certificates.
+func ClientCredentials(k *koanf.Koanf) (credentials.TransportCredentials, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: k.String("grpc.tls.server_name"),
+	}
+	if ca := k.String("grpc.tls.ca"); ca != "" {
+		pool, err := loadCA(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if k.String("grpc.tls.cert") != "" || k.String("grpc.tls.key") != "" {
+		cert, err := tls.LoadX509KeyPair(k.String("grpc.tls.cert"), k.String("grpc.tls.key"))
+		if err != nil {
+			return nil, fmt.Errorf("grpc: loading the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// ServerCredentials returns the TLS credentials of the processor. With a CA,
+// every client must present a certificate signed by it (mTLS).
+func ServerCredentials(k *koanf.Koanf) (credentials.TransportCredentials, error) {
+	if k.String("grpc.tls.cert") == "" || k.String("grpc.tls.key") == "" {
+		return nil, errors.New("grpc: grpc.tls.cert and grpc.tls.key are required to serve TLS")
+	}
+	cert, err := tls.LoadX509KeyPair(k.String("grpc.tls.cert"), k.String("grpc.tls.key"))
+	if err != nil {
+		return nil, fmt.Errorf("grpc: loading the server certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if ca := k.String("grpc.tls.ca"); ca != "" {
+		pool, err := loadCA(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(config), nil
+}
+
+func loadCA(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("grpc: loading the CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("grpc: no certificate found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"spotify/services/spotify"
+
+	"github.com/goccy/go-json"
+	sm "github.com/zmb3/spotify/v2"
+)
+
+// exportEntry is a stream of Spotify's extended streaming history
+// (Streaming_History_Audio_*.json and the older endsong_*.json)
+type exportEntry struct {
+	// When the stream ended
+	TS              string `json:"ts"`
+	MsPlayed        int64  `json:"ms_played"`
+	TrackName       string `json:"master_metadata_track_name"`
+	ArtistName      string `json:"master_metadata_album_artist_name"`
+	AlbumName       string `json:"master_metadata_album_album_name"`
+	TrackURI        string `json:"spotify_track_uri"`
+	EpisodeName     string `json:"episode_name"`
+	EpisodeShowName string `json:"episode_show_name"`
+	EpisodeURI      string `json:"spotify_episode_uri"`
+	ReasonEnd       string `json:"reason_end"`
+	Skipped         *bool  `json:"skipped"`
+}
+
+// ParseExport reads a file of Spotify's extended streaming history, streams
+// without track nor episode (eg: videos or deleted items) are left out
+func ParseExport(r io.Reader) ([]*Play, error) {
+	var entries []exportEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	plays := make([]*Play, 0, len(entries))
+	for _, entry := range entries {
+		ended, err := time.Parse(time.RFC3339, entry.TS)
+		if err != nil {
+			return nil, err
+		}
+
+		var track *spotify.Track
+		switch {
+		case entry.TrackURI != "":
+			id := uriID(entry.TrackURI)
+			track = &spotify.Track{
+				ID:      id,
+				Title:   entry.TrackName,
+				Type:    spotify.TrackItem,
+				URL:     "https://open.spotify.com/track/" + string(id),
+				Artists: []spotify.Artist{{Name: entry.ArtistName}},
+				Album:   &spotify.Album{Name: entry.AlbumName},
+			}
+		case entry.EpisodeURI != "":
+			id := uriID(entry.EpisodeURI)
+			track = &spotify.Track{
+				ID:      id,
+				Title:   entry.EpisodeName,
+				Type:    spotify.EpisodeItem,
+				URL:     "https://open.spotify.com/episode/" + string(id),
+				Artists: []spotify.Artist{},
+				Show:    &spotify.Show{Name: entry.EpisodeShowName},
+			}
+		default:
+			continue
+		}
+
+		plays = append(plays, &Play{
+			MsPlayed:  entry.MsPlayed,
+			PlayedAt:  ended.Add(-time.Duration(entry.MsPlayed) * time.Millisecond),
+			ReasonEnd: entry.ReasonEnd,
+			Skipped:   entry.Skipped != nil && *entry.Skipped,
+			Source:    SourceImport,
+			Track:     track,
+		})
+	}
+	return plays, nil
+}
+
+func uriID(uri string) sm.ID {
+	return sm.ID(uri[strings.LastIndex(uri, ":")+1:])
+}
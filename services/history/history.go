@@ -0,0 +1,295 @@
+package history
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	proto "spotify/protocols"
+	"spotify/services/spotify"
+
+	"github.com/goccy/go-json"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	// Source of the plays
+	SourceLive   = "live"
+	SourceImport = "import"
+)
+
+var (
+	playsBucket = []byte("plays")
+
+	ErrInvalidCursor = errors.New("history: invalid cursor")
+)
+
+// Play represents a track listened past the threshold
+type Play struct {
+	// Milliseconds listened
+	MsPlayed int64 `json:"ms_played"`
+	// When the play started
+	PlayedAt time.Time `json:"played_at"`
+	// Why the play ended, only known for imported plays
+	ReasonEnd string `json:"reason_end,omitempty"`
+	// Whether the track was skipped, only known for imported plays
+	Skipped bool `json:"skipped"`
+	// Where the play comes from ("live" or "import")
+	Source string `json:"source"`
+	// Track played
+	Track *spotify.Track `json:"track"`
+}
+
+// Query filters the listed plays, zero values are ignored
+type Query struct {
+	// Plays started at or after
+	From time.Time
+	// Plays started before
+	To time.Time
+	// Cursor returned by the previous page
+	Cursor string
+	// Max plays to return
+	Limit int
+	// Artist ID or name
+	Artist string
+	// Album ID or name
+	Album string
+}
+
+// Store keeps the plays in a bbolt database, sorted by start time
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(playsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put saves the play, replacing the one of the same track started at the same time
+func (s *Store) Put(play *Play) error {
+	value, err := json.Marshal(play)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(playsBucket).Put(key(play), value)
+	})
+}
+
+// Import saves the plays in a single transaction, skipping the ones of a track
+// already recorded within the tolerance of their start time. It reports how
+// many plays were added.
+func (s *Store) Import(plays []*Play, tolerance time.Duration) (int, error) {
+	added := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playsBucket)
+		for _, play := range plays {
+			recorded, err := isRecorded(bucket.Cursor(), play, tolerance)
+			if err != nil {
+				return err
+			}
+			if recorded {
+				continue
+			}
+
+			value, err := json.Marshal(play)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(key(play), value); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func isRecorded(c *bolt.Cursor, play *Play, tolerance time.Duration) (bool, error) {
+	end := timeKey(play.PlayedAt.Add(tolerance + time.Millisecond))
+	for k, v := c.Seek(timeKey(play.PlayedAt.Add(-tolerance))); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+		var recorded Play
+		if err := json.Unmarshal(v, &recorded); err != nil {
+			return false, err
+		}
+		if recorded.Track != nil && play.Track != nil && recorded.Track.ID == play.Track.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// List returns the plays matching the query, newest first, and the cursor of
+// the next page (empty on the last page)
+func (s *Store) List(q Query) ([]*Play, string, error) {
+	if q.Limit < 1 || q.Limit > MaxLimit {
+		q.Limit = DefaultLimit
+	}
+
+	var seek []byte
+	if q.Cursor != "" {
+		cursor, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil || len(cursor) < 8 {
+			return nil, "", ErrInvalidCursor
+		}
+		seek = cursor
+	} else if !q.To.IsZero() {
+		seek = timeKey(q.To)
+	}
+
+	var from []byte
+	if !q.From.IsZero() {
+		from = timeKey(q.From)
+	}
+
+	plays := make([]*Play, 0, q.Limit)
+	next := ""
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(playsBucket).Cursor()
+
+		var k, v []byte
+		if seek == nil {
+			k, v = c.Last()
+		} else if k, v = c.Seek(seek); k == nil {
+			k, v = c.Last()
+		}
+		// both the cursor and "to" are exclusive
+		for k != nil && seek != nil && bytes.Compare(k, seek) >= 0 {
+			k, v = c.Prev()
+		}
+
+		for ; k != nil; k, v = c.Prev() {
+			if from != nil && bytes.Compare(k, from) < 0 {
+				break
+			}
+
+			var play Play
+			if err := json.Unmarshal(v, &play); err != nil {
+				return err
+			}
+			if !q.matches(&play) {
+				continue
+			}
+
+			if len(plays) == q.Limit {
+				next = base64.RawURLEncoding.EncodeToString(key(plays[len(plays)-1]))
+				break
+			}
+			plays = append(plays, &play)
+		}
+		return nil
+	})
+	return plays, next, err
+}
+
+// Each calls fn with every play started in [from, to), oldest first
+func (s *Store) Each(from, to time.Time, fn func(*Play) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(playsBucket).Cursor()
+
+		k, v := c.First()
+		if !from.IsZero() {
+			k, v = c.Seek(timeKey(from))
+		}
+
+		var end []byte
+		if !to.IsZero() {
+			end = timeKey(to)
+		}
+		for ; k != nil && (end == nil || bytes.Compare(k, end) < 0); k, v = c.Next() {
+			var play Play
+			if err := json.Unmarshal(v, &play); err != nil {
+				return err
+			}
+			if err := fn(&play); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (q *Query) matches(play *Play) bool {
+	track := play.Track
+	if track == nil {
+		return q.Artist == "" && q.Album == ""
+	}
+
+	if q.Album != "" {
+		if track.Album == nil || (string(track.Album.ID) != q.Album && !strings.EqualFold(track.Album.Name, q.Album)) {
+			return false
+		}
+	}
+
+	if q.Artist != "" {
+		for _, artist := range track.Artists {
+			if string(artist.ID) == q.Artist || strings.EqualFold(artist.Name, q.Artist) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// key sorts the plays by start time, the track ID avoids collisions
+func key(play *Play) []byte {
+	id := ""
+	if play.Track != nil {
+		id = string(play.Track.ID)
+	}
+	return append(timeKey(play.PlayedAt), id...)
+}
+
+func timeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixMilli()))
+	return k
+}
+
+func (play *Play) ToProto() *proto.Play {
+	return &proto.Play{
+		MsPlayed:  play.MsPlayed,
+		PlayedAt:  play.PlayedAt.UnixMilli(),
+		ReasonEnd: play.ReasonEnd,
+		Skipped:   play.Skipped,
+		Source:    play.Source,
+		Track:     play.Track.ToProto(),
+	}
+}
+
+func FromProtoToPlay(pb *proto.Play) *Play {
+	return &Play{
+		MsPlayed:  pb.MsPlayed,
+		PlayedAt:  time.UnixMilli(pb.PlayedAt),
+		ReasonEnd: pb.ReasonEnd,
+		Skipped:   pb.Skipped,
+		Source:    pb.Source,
+		Track:     spotify.FromProtoToTrack(pb.Track),
+	}
+}
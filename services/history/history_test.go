@@ -0,0 +1,116 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"spotify/services/spotify"
+
+	sm "github.com/zmb3/spotify/v2"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// putPlays saves a play of the tracks "0".."n-1" every minute from start
+func putPlays(t *testing.T, store *Store, n int) {
+	t.Helper()
+	for i := range n {
+		play := &Play{
+			MsPlayed: 60000,
+			PlayedAt: start.Add(time.Duration(i) * time.Minute),
+			Source:   SourceLive,
+			Track: &spotify.Track{
+				ID:      sm.ID(fmt.Sprint(i)),
+				Artists: []spotify.Artist{{ID: sm.ID(fmt.Sprint("artist", i%2)), Name: fmt.Sprint("Artist ", i%2)}},
+			},
+		}
+		if err := store.Put(play); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ids(plays []*Play) string {
+	s := ""
+	for _, play := range plays {
+		s += string(play.Track.ID)
+	}
+	return s
+}
+
+func TestList(t *testing.T) {
+	store := openStore(t)
+	putPlays(t, store, 5)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{"newest first", Query{}, "43210"},
+		{"to is exclusive", Query{To: start.Add(3 * time.Minute)}, "210"},
+		{"from is inclusive", Query{From: start.Add(time.Minute)}, "4321"},
+		{"range", Query{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}, "21"},
+		{"limit", Query{Limit: 2}, "43"},
+		{"limit over the max", Query{Limit: MaxLimit + 1}, "43210"},
+		{"artist by ID", Query{Artist: "artist1"}, "31"},
+		{"artist by name", Query{Artist: "artist 0"}, "420"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays, _, err := store.List(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(plays); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPages(t *testing.T) {
+	store := openStore(t)
+	putPlays(t, store, 5)
+
+	tests := []struct {
+		name  string
+		query Query
+		pages []string
+	}{
+		{"every play", Query{Limit: 2}, []string{"43", "21", "0"}},
+		{"exact pages", Query{Limit: 5}, []string{"43210"}},
+		{"within a range", Query{Limit: 2, From: start.Add(time.Minute), To: start.Add(4 * time.Minute)}, []string{"32", "1"}},
+		{"filtered", Query{Limit: 1, Artist: "artist1"}, []string{"3", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			for i, want := range tt.pages {
+				plays, next, err := store.List(query)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := ids(plays); got != want {
+					t.Fatalf("page %d: got %q, want %q", i, got, want)
+				}
+				// the cursor is exclusive, the last page has none
+				if last := i == len(tt.pages)-1; last != (next == "") {
+					t.Fatalf("page %d: got the cursor %q", i, next)
+				}
+				query.Cursor = next
+			}
+		})
+	}
+}
+
+func TestListInvalidCursor(t *testing.T) {
+	store := openStore(t)
+	for _, cursor := range []string{"not base64!", "c2hvcnQ"} {
+		if _, _, err := store.List(Query{Cursor: cursor}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: got %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
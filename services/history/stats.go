@@ -0,0 +1,290 @@
+package history
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	proto "spotify/protocols"
+	"spotify/services/spotify"
+)
+
+const (
+	DefaultTopLimit = 10
+	MaxTopLimit     = 100
+
+	// Buckets of the listening time
+	BucketHour = "hour"
+	BucketDay  = "day"
+	BucketWeek = "week"
+	// Buckets of a listening time at most, eg: 41 days by hour
+	MaxBuckets = 1000
+)
+
+var (
+	ErrInvalidBucket  = errors.New("history: bucket must be hour, day or week")
+	ErrTooManyBuckets = fmt.Errorf("history: the range spans more than %d buckets, narrow it or use a larger bucket", MaxBuckets)
+)
+
+// TopItem is a track, artist or album ranked by plays
+type TopItem struct {
+	// Album, set for top albums
+	Album *spotify.Album `json:"album,omitempty"`
+	// Artist, set for top artists and albums
+	Artist *spotify.Artist `json:"artist,omitempty"`
+	// Milliseconds listened
+	MsPlayed int64 `json:"ms_played"`
+	// Times played
+	Plays int64 `json:"plays"`
+	// Track, set for top tracks
+	Track *spotify.Track `json:"track,omitempty"`
+}
+
+// Bucket is the time listened in an hour, day or week
+type Bucket struct {
+	// Milliseconds listened
+	MsPlayed int64 `json:"ms_played"`
+	// Plays started in the bucket
+	Plays int64 `json:"plays"`
+	// Start of the bucket
+	Start time.Time `json:"start"`
+}
+
+// ListeningTime is the time listened in a range, split in buckets
+type ListeningTime struct {
+	// Buckets from the oldest, including the empty ones
+	Buckets []Bucket `json:"buckets"`
+	// Milliseconds listened in the whole range
+	MsPlayed int64 `json:"ms_played"`
+	// Plays in the whole range
+	Plays int64 `json:"plays"`
+	// Time zone of the buckets
+	Timezone string `json:"timezone"`
+}
+
+// TopTracks ranks the tracks played in [from, to)
+func (s *Store) TopTracks(from, to time.Time, limit int) ([]*TopItem, error) {
+	return s.top(from, to, limit, func(play *Play, add func(string, *TopItem)) {
+		track := *play.Track
+		track.PlayedAt = nil
+		add(string(track.ID), &TopItem{Track: &track})
+	})
+}
+
+// TopArtists ranks the artists played in [from, to), every artist of a track counts
+func (s *Store) TopArtists(from, to time.Time, limit int) ([]*TopItem, error) {
+	return s.top(from, to, limit, func(play *Play, add func(string, *TopItem)) {
+		for _, artist := range play.Track.Artists {
+			// imported plays only know the name
+			add(strings.ToLower(artist.Name), &TopItem{Artist: &artist})
+		}
+	})
+}
+
+// TopAlbums ranks the albums played in [from, to)
+func (s *Store) TopAlbums(from, to time.Time, limit int) ([]*TopItem, error) {
+	return s.top(from, to, limit, func(play *Play, add func(string, *TopItem)) {
+		if play.Track.Album == nil || play.Track.Album.Name == "" {
+			return
+		}
+		item := &TopItem{Album: play.Track.Album}
+		key := strings.ToLower(play.Track.Album.Name)
+		if len(play.Track.Artists) > 0 {
+			item.Artist = &play.Track.Artists[0]
+			key += "\x00" + strings.ToLower(item.Artist.Name)
+		}
+		add(key, item)
+	})
+}
+
+// top groups the plays with the keys given by each, ranking them by plays and time listened
+func (s *Store) top(from, to time.Time, limit int, each func(*Play, func(string, *TopItem))) ([]*TopItem, error) {
+	if limit < 1 || limit > MaxTopLimit {
+		limit = DefaultTopLimit
+	}
+
+	items := make(map[string]*TopItem)
+	err := s.Each(from, to, func(play *Play) error {
+		if play.Track == nil {
+			return nil
+		}
+		each(play, func(key string, item *TopItem) {
+			if found, ok := items[key]; ok {
+				// prefer the most complete (eg: live plays have IDs and images)
+				if found.id() == "" && item.id() != "" {
+					item.Plays, item.MsPlayed = found.Plays, found.MsPlayed
+					items[key] = item
+				}
+			} else {
+				items[key] = item
+			}
+			items[key].Plays++
+			items[key].MsPlayed += play.MsPlayed
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]*TopItem, 0, len(items))
+	for _, item := range items {
+		ranked = append(ranked, item)
+	}
+	slices.SortFunc(ranked, func(a, b *TopItem) int {
+		if a.Plays != b.Plays {
+			return cmp.Compare(b.Plays, a.Plays)
+		}
+		return cmp.Compare(b.MsPlayed, a.MsPlayed)
+	})
+	return ranked[:min(limit, len(ranked))], nil
+}
+
+func (item *TopItem) id() string {
+	switch {
+	case item.Track != nil:
+		return string(item.Track.ID)
+	case item.Album != nil:
+		return string(item.Album.ID)
+	case item.Artist != nil:
+		return string(item.Artist.ID)
+	}
+	return ""
+}
+
+// ListeningTime sums the time listened in [from, to) by hour, day or week (starting on monday)
+// of the given location. Without range, it goes from the first to the last play.
+func (s *Store) ListeningTime(from, to time.Time, bucket string, loc *time.Location) (*ListeningTime, error) {
+	if bucket != BucketHour && bucket != BucketDay && bucket != BucketWeek {
+		return nil, ErrInvalidBucket
+	}
+
+	result := &ListeningTime{Buckets: []Bucket{}, Timezone: loc.String()}
+	// keyed by instant, the wall clock repeats when the clocks fall back
+	sums := make(map[int64]*Bucket)
+	first, last := from, to
+	err := s.Each(from, to, func(play *Play) error {
+		start := bucketStart(play.PlayedAt.In(loc), bucket)
+		sum, ok := sums[start.Unix()]
+		if !ok {
+			sum = &Bucket{Start: start}
+			sums[start.Unix()] = sum
+		}
+		sum.Plays++
+		sum.MsPlayed += play.MsPlayed
+		result.Plays++
+		result.MsPlayed += play.MsPlayed
+
+		if first.IsZero() || play.PlayedAt.Before(first) {
+			first = play.PlayedAt
+		}
+		if to.IsZero() && !play.PlayedAt.Before(last) {
+			last = play.PlayedAt.Add(time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil || first.IsZero() || last.IsZero() {
+		return result, err
+	}
+
+	// fill the empty buckets too, so they can be charted as is
+	for start := bucketStart(first.In(loc), bucket); start.Before(last); start = nextBucket(start, bucket) {
+		if len(result.Buckets) == MaxBuckets {
+			return nil, ErrTooManyBuckets
+		}
+		if sum, ok := sums[start.Unix()]; ok {
+			result.Buckets = append(result.Buckets, *sum)
+		} else {
+			result.Buckets = append(result.Buckets, Bucket{Start: start})
+		}
+	}
+	return result, nil
+}
+
+func bucketStart(t time.Time, bucket string) time.Time {
+	year, month, day := t.Date()
+	switch bucket {
+	case BucketHour:
+		// truncated in absolute time with the offset of t, time.Date would pick
+		// another instant for the hours skipped or repeated by DST
+		_, offset := t.Zone()
+		shift := time.Duration(offset) * time.Second
+		return t.Add(shift).Truncate(time.Hour).Add(-shift)
+	case BucketWeek:
+		weekday := (int(t.Weekday()) + 6) % 7 // monday first
+		return time.Date(year, month, day-weekday, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketHour:
+		return bucketStart(start.Add(time.Hour), bucket)
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func (item *TopItem) ToProto() *proto.TopItem {
+	pb := &proto.TopItem{
+		Album:    item.Album.ToProto(),
+		MsPlayed: item.MsPlayed,
+		Plays:    item.Plays,
+		Track:    item.Track.ToProto(),
+	}
+	if item.Artist != nil {
+		pb.Artist = item.Artist.ToProto()
+	}
+	return pb
+}
+
+func FromProtoToTopItem(pb *proto.TopItem) *TopItem {
+	item := &TopItem{
+		Album:    spotify.FromProtoToAlbum(pb.Album),
+		MsPlayed: pb.MsPlayed,
+		Plays:    pb.Plays,
+		Track:    spotify.FromProtoToTrack(pb.Track),
+	}
+	if pb.Artist != nil {
+		artist := spotify.FromProtoToArtist(pb.Artist)
+		item.Artist = &artist
+	}
+	return item
+}
+
+func (lt *ListeningTime) ToProto() *proto.ListeningTimeResponse {
+	buckets := make([]*proto.Bucket, len(lt.Buckets))
+	for i, bucket := range lt.Buckets {
+		buckets[i] = &proto.Bucket{MsPlayed: bucket.MsPlayed, Plays: bucket.Plays, Start: bucket.Start.UnixMilli()}
+	}
+	return &proto.ListeningTimeResponse{
+		Buckets:  buckets,
+		MsPlayed: lt.MsPlayed,
+		Plays:    lt.Plays,
+		Timezone: lt.Timezone,
+	}
+}
+
+func FromProtoToListeningTime(pb *proto.ListeningTimeResponse) *ListeningTime {
+	loc, err := time.LoadLocation(pb.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	buckets := make([]Bucket, len(pb.Buckets))
+	for i, bucket := range pb.Buckets {
+		buckets[i] = Bucket{MsPlayed: bucket.MsPlayed, Plays: bucket.Plays, Start: time.UnixMilli(bucket.Start).In(loc)}
+	}
+	return &ListeningTime{
+		Buckets:  buckets,
+		MsPlayed: pb.MsPlayed,
+		Plays:    pb.Plays,
+		Timezone: pb.Timezone,
+	}
+}
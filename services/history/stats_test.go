@@ -0,0 +1,90 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"spotify/services/spotify"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestListeningTimeHoursAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone  string
+		day   string
+		hours int
+	}{
+		{"America/New_York", "2024-03-10", 23},
+		{"America/New_York", "2024-11-03", 25},
+		{"Europe/Berlin", "2024-03-31", 23},
+		{"Europe/Berlin", "2024-10-27", 25},
+		{"Asia/Kolkata", "2024-03-31", 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.day, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from, err := time.ParseInLocation(time.DateOnly, tt.day, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			to := from.AddDate(0, 0, 1)
+
+			// a play in the middle of every hour of the day
+			store := openStore(t)
+			for at := from.Add(30 * time.Minute); at.Before(to); at = at.Add(time.Hour) {
+				play := &Play{MsPlayed: 1000, PlayedAt: at, Track: &spotify.Track{ID: "track"}}
+				if err := store.Put(play); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lt, err := store.ListeningTime(from, to, BucketHour, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lt.Buckets) != tt.hours {
+				t.Fatalf("got %d buckets, want %d", len(lt.Buckets), tt.hours)
+			}
+			for i, bucket := range lt.Buckets {
+				if want := from.Add(time.Duration(i) * time.Hour); !bucket.Start.Equal(want) {
+					t.Errorf("bucket %d starts at %s, want %s", i, bucket.Start, want.In(loc))
+				}
+				if bucket.Start.Minute() != 0 {
+					t.Errorf("bucket %d starts at %s, not on the hour", i, bucket.Start)
+				}
+				if bucket.Plays != 1 {
+					t.Errorf("bucket %d (%s) has %d plays, want 1", i, bucket.Start, bucket.Plays)
+				}
+			}
+		})
+	}
+}
+
+func TestListeningTimeTooManyBuckets(t *testing.T) {
+	store := openStore(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.Put(&Play{MsPlayed: 1000, PlayedAt: from, Track: &spotify.Track{ID: "track"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.ListeningTime(from, from.Add(MaxBuckets*time.Hour), BucketHour, time.UTC); err != nil {
+		t.Fatalf("got %v for %d buckets", err, MaxBuckets)
+	}
+	if _, err := store.ListeningTime(from, from.Add((MaxBuckets+1)*time.Hour), BucketHour, time.UTC); err != ErrTooManyBuckets {
+		t.Fatalf("got %v for %d buckets, want ErrTooManyBuckets", err, MaxBuckets+1)
+	}
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/knadh/koanf/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+)
+
+// Namespace prefixes every metric
+const Namespace = "spotify"
+
+// Registry holds the metrics of every package, each one registers its own
+// through Factory
+var Registry = prometheus.NewRegistry()
+
+// Factory registers the metrics to Registry
+var Factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// FiberHandler serves the metrics on a route of the gateway
+func FiberHandler() fiber.Handler {
+	return adaptor.HTTPHandler(Handler())
+}
+
+// Serve serves /metrics on metrics.port, for the processes without HTTP
+// server. Nothing is served without a port.
+func Serve(lc fx.Lifecycle, k *koanf.Koanf) {
+	port := k.Int("metrics.port")
+	if port == 0 {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	srv := &http.Server{Handler: mux}
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			list, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+			if err != nil {
+				return err
+			}
+			go func() {
+				log.Printf("Serving metrics on \"%s\"\n", list.Addr().String())
+				if err := srv.Serve(list); err != nil && err != http.ErrServerClosed {
+					log.Fatal(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+	})
+}
@@ -0,0 +1,266 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"spotify/services/spotify"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/goccy/go-json"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	DefaultBroker          = "tcp://localhost:1883"
+	DefaultClientID        = "spotify-server"
+	DefaultUser            = "me"
+	DefaultQoS             = 1
+	DefaultDiscoveryPrefix = "homeassistant"
+
+	// Payloads of the availability and playing topics
+	Online  = "online"
+	Offline = "offline"
+	On      = "ON"
+	Off     = "OFF"
+
+	connectTimeout = 10 * time.Second
+)
+
+var invalidID = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Topics the track is published to
+type Topics struct {
+	// Whole track as JSON
+	State string
+	// "Artists - Title" of the track
+	Track string
+	// "ON" while playing, "OFF" otherwise
+	Playing string
+	// "online" while connected, "offline" (Last Will) otherwise
+	Availability string
+}
+
+// Publisher publishes the current track as retained messages, along with the
+// Home Assistant discovery of its sensors
+type Publisher struct {
+	client paho.Client
+	user   string
+
+	Topics Topics
+	QoS    byte
+	// Retain the track topics, so new subscribers get the current track
+	Retain bool
+	// DiscoveryPrefix of Home Assistant, empty when discovery is disabled
+	DiscoveryPrefix string
+
+	mu    sync.Mutex
+	state *spotify.State
+}
+
+func New(k *koanf.Koanf) (*Publisher, error) {
+	user := k.String("mqtt.user")
+	if user == "" {
+		user = DefaultUser
+	}
+	prefix := k.String("mqtt.topic_prefix")
+	if prefix == "" {
+		prefix = "spotify/" + user
+	}
+	topic := func(name string) string {
+		if topic := k.String("mqtt.topics." + name); topic != "" {
+			return topic
+		}
+		return prefix + "/" + name
+	}
+
+	qos := DefaultQoS
+	if k.Exists("mqtt.qos") {
+		qos = k.Int("mqtt.qos")
+	}
+	if qos < 0 || qos > 2 {
+		return nil, errors.New("mqtt: qos must be 0, 1 or 2")
+	}
+
+	p := &Publisher{
+		user: user,
+		Topics: Topics{
+			State:        topic("state"),
+			Track:        topic("track"),
+			Playing:      topic("playing"),
+			Availability: topic("availability"),
+		},
+		QoS:    byte(qos),
+		Retain: !k.Exists("mqtt.retain") || k.Bool("mqtt.retain"),
+	}
+	if !k.Exists("mqtt.discovery.enabled") || k.Bool("mqtt.discovery.enabled") {
+		p.DiscoveryPrefix = k.String("mqtt.discovery.prefix")
+		if p.DiscoveryPrefix == "" {
+			p.DiscoveryPrefix = DefaultDiscoveryPrefix
+		}
+	}
+
+	broker := k.String("mqtt.broker")
+	if broker == "" {
+		broker = DefaultBroker
+	}
+	clientID := k.String("mqtt.client_id")
+	if clientID == "" {
+		clientID = DefaultClientID
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetUsername(k.String("mqtt.username")).
+		SetPassword(k.String("mqtt.password")).
+		SetAutoReconnect(true).
+		SetConnectTimeout(connectTimeout).
+		// the broker tells everyone we are gone if the connection drops
+		SetWill(p.Topics.Availability, Offline, p.QoS, true).
+		SetOnConnectHandler(p.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Printf("MQTT connection lost: %v", err)
+		})
+	p.client = paho.NewClient(opts)
+	return p, nil
+}
+
+// Connect connects to the broker, later disconnections reconnect on their own
+func (p *Publisher) Connect() error {
+	token := p.client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return errors.New("mqtt: timeout while connecting to the broker")
+	}
+	return token.Error()
+}
+
+// Close marks the publisher offline and disconnects
+func (p *Publisher) Close() {
+	if p.client.IsConnected() {
+		// a clean disconnection doesn't fire the Last Will
+		p.client.Publish(p.Topics.Availability, p.QoS, true, Offline).WaitTimeout(time.Second)
+	}
+	p.client.Disconnect(250)
+}
+
+// Publish publishes the state to every topic, states without track are ignored
+func (p *Publisher) Publish(state *spotify.State) {
+	if state == nil || state.Track == nil {
+		return
+	}
+	p.mu.Lock()
+	p.state = state
+	p.mu.Unlock()
+
+	if p.client.IsConnected() {
+		p.publishState(state)
+	}
+}
+
+// Current returns the state published last
+func (p *Publisher) Current() *spotify.State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state
+}
+
+// onConnect (re)announces the publisher, retained messages may have been lost
+// if the broker restarted
+func (p *Publisher) onConnect(_ paho.Client) {
+	log.Println("Connected to the MQTT broker")
+	p.publish(p.Topics.Availability, Online, true)
+	if p.DiscoveryPrefix != "" {
+		p.publishDiscovery()
+	}
+	if state := p.Current(); state != nil {
+		p.publishState(state)
+	}
+}
+
+func (p *Publisher) publishState(state *spotify.State) {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("error while publishing to MQTT: %v", err)
+		return
+	}
+	playing := Off
+	if state.Track.IsPlaying {
+		playing = On
+	}
+
+	p.publish(p.Topics.State, payload, p.Retain)
+	p.publish(p.Topics.Track, Title(state.Track), p.Retain)
+	p.publish(p.Topics.Playing, playing, p.Retain)
+}
+
+// publishDiscovery announces a sensor with the track (and the state as its
+// attributes) and a binary sensor of the playback to Home Assistant
+func (p *Publisher) publishDiscovery() {
+	id := "spotify_" + invalidID.ReplaceAllString(p.user, "_")
+	device := map[string]any{
+		"identifiers":  []string{id},
+		"name":         "Spotify " + p.user,
+		"manufacturer": "Spotify-Server",
+	}
+
+	for component, config := range map[string]map[string]any{
+		"sensor": {
+			"name":                  "Track",
+			"unique_id":             id + "_track",
+			"state_topic":           p.Topics.Track,
+			"json_attributes_topic": p.Topics.State,
+			"icon":                  "mdi:spotify",
+		},
+		"binary_sensor": {
+			"name":        "Playing",
+			"unique_id":   id + "_playing",
+			"state_topic": p.Topics.Playing,
+			"payload_on":  On,
+			"payload_off": Off,
+			"icon":        "mdi:play-pause",
+		},
+	} {
+		config["availability_topic"] = p.Topics.Availability
+		config["payload_available"] = Online
+		config["payload_not_available"] = Offline
+		config["device"] = device
+
+		payload, err := json.Marshal(config)
+		if err != nil {
+			log.Printf("error while publishing to MQTT: %v", err)
+			continue
+		}
+		p.publish(fmt.Sprintf("%s/%s/%s/%s/config", p.DiscoveryPrefix, component, id, config["unique_id"]), payload, true)
+	}
+}
+
+// publish sends without waiting, availability and discovery are always retained
+func (p *Publisher) publish(topic string, payload any, retained bool) {
+	token := p.client.Publish(topic, p.QoS, retained, payload)
+	go func() {
+		if token.Wait(); token.Error() != nil {
+			log.Printf("error while publishing to MQTT topic %s: %v", topic, token.Error())
+		}
+	}()
+}
+
+// Title returns "Artists - Title" of a track, or "Show - Title" of an episode
+func Title(track *spotify.Track) string {
+	if track.Show != nil && track.Show.Name != "" {
+		return track.Show.Name + " - " + track.Title
+	}
+	names := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		names = append(names, artist.Name)
+	}
+	if len(names) == 0 {
+		return track.Title
+	}
+	return strings.Join(names, ", ") + " - " + track.Title
+}
@@ -0,0 +1,229 @@
+package mqtt
+
+import (
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"spotify/services/spotify"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/goccy/go-json"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+)
+
+const waitTimeout = 5 * time.Second
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// startBroker runs an in-process broker, returning it and its address
+func startBroker(t *testing.T) (*mochi.Server, string) {
+	t.Helper()
+	server := mochi.New(&mochi.Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := server.AddListener(tcp); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server, "tcp://" + tcp.Address()
+}
+
+func newPublisher(t *testing.T, broker string, conf map[string]any) *Publisher {
+	t.Helper()
+	k := koanf.New(".")
+	conf["mqtt.broker"] = broker
+	if err := k.Load(confmap.Provider(conf, "."), nil); err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// subscribe collects the messages of the topics matching filter on a new
+// connection, the retained ones come first
+func subscribe(t *testing.T, broker, filter string) <-chan paho.Message {
+	t.Helper()
+	messages := make(chan paho.Message, 64)
+	client := paho.NewClient(paho.NewClientOptions().AddBroker(broker).SetClientID("test-" + strings.ReplaceAll(t.Name(), "/", "-")))
+	if token := client.Connect(); !token.WaitTimeout(waitTimeout) || token.Error() != nil {
+		t.Fatalf("connecting the subscriber: %v", token.Error())
+	}
+	t.Cleanup(func() { client.Disconnect(0) })
+
+	token := client.Subscribe(filter, 2, func(_ paho.Client, msg paho.Message) {
+		messages <- msg
+	})
+	if !token.WaitTimeout(waitTimeout) || token.Error() != nil {
+		t.Fatalf("subscribing to %s: %v", filter, token.Error())
+	}
+	return messages
+}
+
+// collect waits for a message on each of the topics, keeping the last one
+func collect(t *testing.T, messages <-chan paho.Message, topics ...string) map[string]paho.Message {
+	t.Helper()
+	got := make(map[string]paho.Message)
+	timeout := time.After(waitTimeout)
+	for len(got) < len(topics) {
+		select {
+		case msg := <-messages:
+			for _, topic := range topics {
+				if msg.Topic() == topic {
+					got[topic] = msg
+				}
+			}
+		case <-timeout:
+			t.Fatalf("got messages on %d of the topics %v", len(got), topics)
+		}
+	}
+	return got
+}
+
+// waitRetained waits until the broker retains n messages
+func waitRetained(t *testing.T, server *mochi.Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for server.Topics.Retained.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("the broker retains %d messages, want %d", server.Topics.Retained.Len(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPublishRetainsTrack(t *testing.T) {
+	server, broker := startBroker(t)
+	p := newPublisher(t, broker, map[string]any{"mqtt.qos": 2, "mqtt.discovery.enabled": false})
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	track := &spotify.Track{ID: "track", Title: "Title", IsPlaying: true, Artists: []spotify.Artist{{Name: "A"}, {Name: "B"}}}
+	p.Publish(&spotify.State{Track: track, Player: &spotify.Player{Repeat: "off", Device: &spotify.Device{Name: "Phone"}}})
+	// availability, state, track and playing
+	waitRetained(t, server, 4)
+
+	topics := []string{p.Topics.State, p.Topics.Track, p.Topics.Playing, p.Topics.Availability}
+	got := collect(t, subscribe(t, broker, "spotify/me/#"), topics...)
+	for _, topic := range topics {
+		if msg := got[topic]; !msg.Retained() || msg.Qos() != 2 {
+			t.Errorf("%s: retained %t with QoS %d, want retained with QoS 2", topic, msg.Retained(), msg.Qos())
+		}
+	}
+
+	var state spotify.State
+	if err := json.Unmarshal(got[p.Topics.State].Payload(), &state); err != nil || state.Track == nil || state.Track.ID != track.ID || state.Player == nil || state.Player.Device.Name != "Phone" {
+		t.Errorf("state: got %s (%v), want the track and the player", got[p.Topics.State].Payload(), err)
+	}
+	if payload := string(got[p.Topics.Track].Payload()); payload != "A, B - Title" {
+		t.Errorf("track: got %q", payload)
+	}
+	if payload := string(got[p.Topics.Playing].Payload()); payload != On {
+		t.Errorf("playing: got %q, want %q", payload, On)
+	}
+	if payload := string(got[p.Topics.Availability].Payload()); payload != Online {
+		t.Errorf("availability: got %q, want %q", payload, Online)
+	}
+}
+
+func TestPublishDiscovery(t *testing.T) {
+	server, broker := startBroker(t)
+	p := newPublisher(t, broker, map[string]any{"mqtt.user": "some.one"})
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	// availability and the config of both sensors
+	waitRetained(t, server, 3)
+
+	sensor := "homeassistant/sensor/spotify_some_one/spotify_some_one_track/config"
+	binary := "homeassistant/binary_sensor/spotify_some_one/spotify_some_one_playing/config"
+	got := collect(t, subscribe(t, broker, "homeassistant/#"), sensor, binary)
+
+	tests := []struct {
+		topic string
+		want  map[string]any
+	}{
+		{sensor, map[string]any{
+			"state_topic":           p.Topics.Track,
+			"json_attributes_topic": p.Topics.State,
+			"availability_topic":    p.Topics.Availability,
+			"payload_available":     Online,
+			"payload_not_available": Offline,
+		}},
+		{binary, map[string]any{
+			"state_topic":        p.Topics.Playing,
+			"payload_on":         On,
+			"payload_off":        Off,
+			"availability_topic": p.Topics.Availability,
+		}},
+	}
+	for _, tt := range tests {
+		msg := got[tt.topic]
+		if !msg.Retained() || msg.Qos() != DefaultQoS {
+			t.Errorf("%s: retained %t with QoS %d, want retained with QoS %d", tt.topic, msg.Retained(), msg.Qos(), DefaultQoS)
+		}
+		var config map[string]any
+		if err := json.Unmarshal(msg.Payload(), &config); err != nil {
+			t.Fatalf("%s: %v", tt.topic, err)
+		}
+		for key, want := range tt.want {
+			if config[key] != want {
+				t.Errorf("%s: %s is %v, want %v", tt.topic, key, config[key], want)
+			}
+		}
+		if device, _ := config["device"].(map[string]any); device == nil || device["name"] != "Spotify some.one" {
+			t.Errorf("%s: device is %v", tt.topic, config["device"])
+		}
+	}
+}
+
+func TestLastWill(t *testing.T) {
+	server, broker := startBroker(t)
+	p := newPublisher(t, broker, map[string]any{"mqtt.qos": 0, "mqtt.client_id": "publisher", "mqtt.discovery.enabled": false})
+	if err := p.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	client, ok := server.Clients.Get("publisher")
+	if !ok {
+		t.Fatal("the publisher isn't connected")
+	}
+	will := client.Properties.Will
+	if will.TopicName != p.Topics.Availability || string(will.Payload) != Offline || !will.Retain || will.Qos != 0 {
+		t.Fatalf("got the will %q on %s (retain %t, QoS %d)", will.Payload, will.TopicName, will.Retain, will.Qos)
+	}
+
+	messages := subscribe(t, broker, p.Topics.Availability)
+	if msg := collect(t, messages, p.Topics.Availability)[p.Topics.Availability]; string(msg.Payload()) != Online {
+		t.Fatalf("got %q before the connection dropped, want %q", msg.Payload(), Online)
+	}
+	// drop the connection without disconnecting, as a crash would
+	client.Stop(errors.New("dropped"))
+	if msg := collect(t, messages, p.Topics.Availability)[p.Topics.Availability]; string(msg.Payload()) != Offline {
+		t.Fatalf("got %q once the connection dropped, want %q", msg.Payload(), Offline)
+	}
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// demand keeps the websocket listeners reported by every gateway, so the
+// poller can slow down while nobody is listening.
+type demand struct {
+	mu        sync.Mutex
+	listeners map[string]int64
+	wake      chan struct{}
+	// latest stream of every gateway, a reconnected gateway opens a new one
+	// before the previous one is closed
+	streams   map[string]uint64
+	streamSeq uint64
+}
+
+func newDemand() *demand {
+	return &demand{
+		listeners: make(map[string]int64),
+		wake:      make(chan struct{}),
+		streams:   make(map[string]uint64),
+	}
+}
+
+// Set stores the listeners of a gateway and returns the total.
+// Going from no demand to some demand wakes up every waiting poller.
+func (d *demand) Set(id string, listeners int64) int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(id, listeners)
+}
+
+// Open registers a new stream of the gateway, it returns the stream to close
+func (d *demand) Open(id string) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.streamSeq++
+	d.streams[id] = d.streamSeq
+	return d.streamSeq
+}
+
+// Close forgets the listeners of the gateway, unless it opened another stream
+// since this one
+func (d *demand) Close(id string, stream uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.streams[id] != stream {
+		return
+	}
+	delete(d.streams, id)
+	d.set(id, 0)
+}
+
+func (d *demand) set(id string, listeners int64) int64 {
+	before := d.total()
+	if listeners > 0 {
+		d.listeners[id] = listeners
+	} else {
+		delete(d.listeners, id)
+	}
+
+	after := d.total()
+	if before == 0 && after > 0 {
+		close(d.wake)
+		d.wake = make(chan struct{})
+	}
+	return after
+}
+
+func (d *demand) Total() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total()
+}
+
+func (d *demand) total() int64 {
+	var total int64
+	for _, listeners := range d.listeners {
+		total += listeners
+	}
+	return total
+}
+
+// Wake wakes up every waiting poller, eg: to apply new poll rates.
+func (d *demand) Wake() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	close(d.wake)
+	d.wake = make(chan struct{})
+}
+
+// Wait sleeps for the given duration, until demand comes back or ctx is done.
+func (d *demand) Wait(ctx context.Context, duration time.Duration) {
+	d.mu.Lock()
+	wake := d.wake
+	d.mu.Unlock()
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-wake:
+	case <-ctx.Done():
+	}
+}
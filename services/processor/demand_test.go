@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDemandReconnect(t *testing.T) {
+	d := newDemand()
+	old := d.Open("gateway")
+	d.Set("gateway", 3)
+
+	// the gateway reconnects before its previous stream is closed
+	current := d.Open("gateway")
+	d.Set("gateway", 2)
+	d.Close("gateway", old)
+	if total := d.Total(); total != 2 {
+		t.Fatalf("the previous stream left %d listeners, want 2", total)
+	}
+
+	d.Close("gateway", current)
+	if total := d.Total(); total != 0 {
+		t.Fatalf("the last stream left %d listeners, want 0", total)
+	}
+}
+
+func TestDemandWaitCancelled(t *testing.T) {
+	d := newDemand()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.Wait(ctx, time.Hour)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait ignored the cancelled context")
+	}
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// SpotifyHealth is the health service of the Spotify polling, the overall
+// health ("") follows it
+const SpotifyHealth = "spotify"
+
+// Health returns the grpc.health.v1 service of the processor
+func (s *Server) Health() healthgrpc.HealthServer {
+	return s.health
+}
+
+func newHealth(serving bool) *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", servingStatus(serving))
+	h.SetServingStatus(SpotifyHealth, servingStatus(serving))
+	return h
+}
+
+// setServing reports whether Spotify is polled successfully, it must be called
+// with pollMu held
+func (s *Server) setServing(serving bool) {
+	if s.serving == serving {
+		return
+	}
+	s.serving = serving
+	s.health.SetServingStatus("", servingStatus(serving))
+	s.health.SetServingStatus(SpotifyHealth, servingStatus(serving))
+
+	if serving {
+		pollBackoff.Set(0)
+	} else {
+		pollBackoff.Set(1)
+	}
+}
+
+func servingStatus(serving bool) healthgrpc.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthgrpc.HealthCheckResponse_SERVING
+	}
+	return healthgrpc.HealthCheckResponse_NOT_SERVING
+}
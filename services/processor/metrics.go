@@ -0,0 +1,28 @@
+package processor
+
+import (
+	"spotify/services/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pollInterval = metrics.Factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "poller",
+		Name:      "interval_seconds",
+		Help:      "Wait before the next poll of Spotify.",
+	})
+	pollBackoff = metrics.Factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "poller",
+		Name:      "backoff",
+		Help:      "1 while the poller backs off after a failed poll, 0 otherwise.",
+	})
+	subscribers = metrics.Factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "processor",
+		Name:      "subscribers",
+		Help:      "Open OnListen streams.",
+	})
+)
@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"spotify/protocols"
+
+	sm "github.com/zmb3/spotify/v2"
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Time Spotify takes to reflect a command in the player state
+const controlSettle = 300 * time.Millisecond
+
+// control runs a player command and polls right away, so every listener sees
+// the change at once. It returns the resulting state.
+func (s *Server) control(ctx context.Context, req *protocols.PlayerRequest, command func(context.Context, *sm.PlayOptions) error) (*protocols.State, error) {
+	if err := s.requireScope(spotifyauth.ScopeUserModifyPlaybackState); err != nil {
+		return nil, err
+	}
+
+	opts := &sm.PlayOptions{}
+	if req.GetDeviceID() != "" {
+		device := sm.ID(req.GetDeviceID())
+		opts.DeviceID = &device
+	}
+	if err := command(ctx, opts); err != nil {
+		return nil, controlError(err)
+	}
+
+	select {
+	case <-time.After(controlSettle):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	// the poll outlives the call, the listeners get the state either way
+	state, err := s.poll(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return state.ToProto(), nil
+}
+
+func (s *Server) Play(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	return s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		if req.ContextURI != nil {
+			uri := sm.URI(req.GetContextURI())
+			opts.PlaybackContext = &uri
+		}
+		for _, uri := range req.GetURIs() {
+			opts.URIs = append(opts.URIs, sm.URI(uri))
+		}
+		opts.PositionMs = sm.Numeric(req.GetPosition())
+		return s.spotify.Client.PlayOpt(ctx, opts)
+	})
+}
+
+func (s *Server) Pause(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	return s.control(ctx, req, s.spotify.Client.PauseOpt)
+}
+
+func (s *Server) Next(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	return s.control(ctx, req, s.spotify.Client.NextOpt)
+}
+
+func (s *Server) Previous(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	return s.control(ctx, req, s.spotify.Client.PreviousOpt)
+}
+
+func (s *Server) Seek(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	if req.Position == nil || req.GetPosition() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must be a positive amount of milliseconds")
+	}
+	return s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		return s.spotify.Client.SeekOpt(ctx, int(req.GetPosition()), opts)
+	})
+}
+
+func (s *Server) Volume(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	if req.Volume == nil || req.GetVolume() < 0 || req.GetVolume() > 100 {
+		return nil, status.Error(codes.InvalidArgument, "volume must be between 0 and 100")
+	}
+	return s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		return s.spotify.Client.VolumeOpt(ctx, int(req.GetVolume()), opts)
+	})
+}
+
+func (s *Server) Shuffle(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	if req.Shuffle == nil {
+		return nil, status.Error(codes.InvalidArgument, "shuffle state is required")
+	}
+	return s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		return s.spotify.Client.ShuffleOpt(ctx, req.GetShuffle(), opts)
+	})
+}
+
+func (s *Server) Repeat(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	switch req.GetRepeat() {
+	case "off", "track", "context":
+	default:
+		return nil, status.Error(codes.InvalidArgument, "repeat state must be off, track or context")
+	}
+	return s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		return s.spotify.Client.RepeatOpt(ctx, req.GetRepeat(), opts)
+	})
+}
+
+func (s *Server) AddToQueue(ctx context.Context, req *protocols.PlayerRequest) (*protocols.State, error) {
+	// only tracks can be queued through the library
+	id, ok := strings.CutPrefix(req.GetURI(), "spotify:track:")
+	if !ok || id == "" {
+		return nil, status.Error(codes.InvalidArgument, "uri must be a track URI (spotify:track:...)")
+	}
+	state, err := s.control(ctx, req, func(ctx context.Context, opts *sm.PlayOptions) error {
+		return s.spotify.Client.QueueSongOpt(ctx, sm.ID(id), opts)
+	})
+	if err == nil {
+		if _, err := s.refreshQueue(ctx); err != nil {
+			log.Printf("error while fetching the queue: %v", err)
+		}
+	}
+	return state, err
+}
+
+// controlError keeps the meaning of Spotify's errors, eg: no active device or premium required
+func controlError(err error) error {
+	var spotifyErr sm.Error
+	if !errors.As(err, &spotifyErr) {
+		return err
+	}
+	switch spotifyErr.Status {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, spotifyErr.Message)
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, spotifyErr.Message)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, spotifyErr.Message)
+	case http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, spotifyErr.Message)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"log"
+	"time"
+
+	"spotify/services/history"
+	"spotify/services/spotify"
+)
+
+const (
+	// Listened time to record a play, shorter tracks need half their duration
+	DefaultPlayThreshold = 30 * time.Second
+
+	// Going back further than this on the same track starts a new play
+	replayTolerance = 5 * time.Second
+)
+
+// plays follows the polled tracks and records the ones listened past the threshold
+type plays struct {
+	store     *history.Store
+	threshold time.Duration
+
+	current  *history.Play
+	progress time.Duration
+	recorded bool
+}
+
+func newPlays(store *history.Store, threshold time.Duration) *plays {
+	return &plays{store: store, threshold: threshold}
+}
+
+// Observe must be called with every polled track, from a single goroutine
+func (p *plays) Observe(track *spotify.Track) {
+	// nothing playing or ads, the play in progress (if any) keeps going
+	if track == nil || track.Timestamp == nil || track.ID == "" {
+		return
+	}
+
+	progress := time.Duration(track.Timestamp.Progress) * time.Millisecond
+	if p.current == nil || p.current.Track.ID != track.ID || progress+replayTolerance < p.progress {
+		p.finish()
+
+		played := *track
+		played.IsPlaying, played.Timestamp = false, nil
+		p.current = &history.Play{
+			PlayedAt: time.Now().Add(-progress),
+			Source:   history.SourceLive,
+			Track:    &played,
+		}
+		p.recorded = false
+	}
+
+	p.progress = progress
+	p.current.MsPlayed = max(p.current.MsPlayed, progress.Milliseconds())
+
+	threshold := p.threshold
+	if duration := time.Duration(track.Timestamp.Duration) * time.Millisecond / 2; duration < threshold {
+		threshold = duration
+	}
+	if !p.recorded && progress >= threshold {
+		p.recorded = true
+		p.save()
+	}
+}
+
+// finish saves the time listened of the play in progress
+func (p *plays) finish() {
+	if p.current != nil && p.recorded {
+		p.save()
+	}
+}
+
+func (p *plays) save() {
+	if err := p.store.Put(p.current); err != nil {
+		log.Printf("error while recording play: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"spotify/services/history"
+	"spotify/services/spotify"
+
+	sm "github.com/zmb3/spotify/v2"
+)
+
+func track(id string, duration, progress time.Duration) *spotify.Track {
+	return &spotify.Track{
+		ID:        sm.ID(id),
+		Title:     id,
+		IsPlaying: true,
+		Timestamp: &spotify.Timestamp{Progress: sm.Numeric(progress.Milliseconds()), Duration: sm.Numeric(duration.Milliseconds())},
+	}
+}
+
+func TestPlaysObserve(t *testing.T) {
+	type play struct {
+		id       string
+		msPlayed int64
+	}
+	tests := []struct {
+		name  string
+		polls []*spotify.Track
+		want  []play
+	}{
+		{"under the threshold", []*spotify.Track{
+			track("a", 3*time.Minute, 0),
+			track("a", 3*time.Minute, 29*time.Second),
+			track("b", 3*time.Minute, 0),
+		}, nil},
+		{"past the threshold", []*spotify.Track{
+			track("a", 3*time.Minute, 0),
+			track("a", 3*time.Minute, 30*time.Second),
+		}, []play{{"a", 30000}}},
+		{"listened time saved when the next track starts", []*spotify.Track{
+			track("a", 3*time.Minute, 30*time.Second),
+			track("a", 3*time.Minute, 100*time.Second),
+			track("b", 3*time.Minute, 0),
+		}, []play{{"a", 100000}}},
+		{"half of a short track", []*spotify.Track{
+			track("a", 40*time.Second, 20*time.Second),
+		}, []play{{"a", 20000}}},
+		{"seeking back a little", []*spotify.Track{
+			track("a", 3*time.Minute, 60*time.Second),
+			track("a", 3*time.Minute, 56*time.Second),
+			track("a", 3*time.Minute, 90*time.Second),
+			track("b", 3*time.Minute, 0),
+		}, []play{{"a", 90000}}},
+		{"replayed", []*spotify.Track{
+			track("a", 3*time.Minute, 60*time.Second),
+			track("a", 3*time.Minute, 0),
+			track("a", 3*time.Minute, 45*time.Second),
+		}, []play{{"a", 45000}, {"a", 60000}}},
+		{"nothing playing and ads keep the play", []*spotify.Track{
+			track("a", 3*time.Minute, 20*time.Second),
+			nil,
+			{Title: "Advertisement", Type: spotify.AdItem, Timestamp: &spotify.Timestamp{Progress: 5000}},
+			track("a", 3*time.Minute, 40*time.Second),
+		}, []play{{"a", 40000}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+
+			p := newPlays(store, DefaultPlayThreshold)
+			for _, polled := range tt.polls {
+				p.Observe(polled)
+			}
+
+			// newest first
+			recorded, _, err := store.List(history.Query{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(recorded) != len(tt.want) {
+				t.Fatalf("recorded %d plays, want %d", len(recorded), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := recorded[i]
+				if string(got.Track.ID) != want.id || got.MsPlayed != want.msPlayed || got.Source != history.SourceLive {
+					t.Errorf("play %d: got %s for %dms from %s, want %s for %dms", i, got.Track.ID, got.MsPlayed, got.Source, want.id, want.msPlayed)
+				}
+				if got.Track.Timestamp != nil || got.Track.IsPlaying {
+					t.Errorf("play %d: the state of the player was recorded", i)
+				}
+			}
+		})
+	}
+}
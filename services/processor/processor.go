@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"spotify/protocols"
+	"spotify/services/config"
+	"spotify/services/history"
+	"spotify/services/scrobble"
+	"spotify/services/webhook"
+
+	"github.com/knadh/koanf/v2"
+	"go.uber.org/fx"
+)
+
+const (
+	// Poll rate (in seconds) used while no gateway reports listeners
+	DefaultIdlePollRate time.Duration = 60
+	// Time a track change may wait for its artists to be enriched
+	DefaultEnrichBudget = 250 * time.Millisecond
+	// Seconds between queue refreshes while the track doesn't change
+	DefaultQueuePollRate time.Duration = 30
+	// Database of the listening history
+	DefaultHistoryPath = "history.db"
+)
+
+// Module provides the processor and polls Spotify while the app runs. It needs
+// the Spotify client and leaves serving it to the app, either over the network
+// or embedded in the gateway.
+var Module = fx.Module("processor",
+	fx.Provide(
+		OpenHistory,
+		webhook.New,
+		scrobble.New,
+		New,
+		func(s *Server) protocols.SpotifyServer { return s },
+		(*Server).Health,
+	),
+	fx.Invoke(Run, ConfigureWebhooks, ConfigureScrobbler),
+)
+
+// Run polls Spotify and the queue until the app stops, the poll rates follow
+// the reloads of the configuration
+func Run(lc fx.Lifecycle, s *Server, w *config.Watcher) {
+	w.OnReload(s.setPollRates)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go s.pool(ctx)
+			go s.queueLoop(ctx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			// NOT_SERVING to every watcher while stopping
+			s.health.Shutdown()
+			return nil
+		},
+	})
+}
+
+func OpenHistory(lc fx.Lifecycle, k *koanf.Koanf) (*history.Store, error) {
+	path := k.String("history.path")
+	if path == "" {
+		path = DefaultHistoryPath
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return store.Close()
+		},
+	})
+	return store, nil
+}
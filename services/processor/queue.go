@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"spotify/protocols"
+	"spotify/services/spotify"
+)
+
+// queueLoop refreshes the queue on a slow cadence while someone listens, track
+// changes and player commands refresh it as well
+func (s *Server) queueLoop(ctx context.Context) {
+	_, rate := s.pollRates()
+	ticker := time.NewTicker(rate * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.queueRate:
+			_, rate := s.pollRates()
+			ticker.Reset(rate * time.Second)
+		case <-ticker.C:
+			if s.spotify.IsConnected() && s.demand.Total() > 0 {
+				if _, err := s.refreshQueue(ctx); err != nil {
+					log.Printf("error while fetching the queue: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// refreshQueue fetches the queue and hands it to the listeners when the items
+// up next differ from the last snapshot
+func (s *Server) refreshQueue(ctx context.Context) (*spotify.Queue, error) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	queue, err := s.spotify.GetQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := !queue.SameItems(s.queue)
+	s.queue, s.queuedAt = queue, time.Now()
+	if changed {
+		for _, onQueue := range s.queueListeners.All() {
+			onQueue(ctx, queue)
+		}
+	}
+	return queue, nil
+}
+
+func (s *Server) GetQueue(ctx context.Context, _ *protocols.Request) (*protocols.Queue, error) {
+	s.queueMu.Lock()
+	queue := s.queue
+	// the cadence pauses while nobody listens
+	_, rate := s.pollRates()
+	stale := time.Since(s.queuedAt) > rate*time.Second
+	s.queueMu.Unlock()
+
+	if queue == nil || stale {
+		var err error
+		if queue, err = s.refreshQueue(ctx); err != nil {
+			return nil, controlError(err)
+		}
+	}
+	return queue.ToProto(), nil
+}
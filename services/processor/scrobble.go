@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"context"
+
+	"spotify/services/scrobble"
+
+	"go.uber.org/fx"
+)
+
+// ID the scrobbler reports its demand with
+const scrobblerID = "scrobbler"
+
+// ConfigureScrobbler submits the scrobbles while the processor runs
+func ConfigureScrobbler(lc fx.Lifecycle, s *Server, scrobbler *scrobble.Scrobbler) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			// an idle poller would miss the end of short tracks
+			if scrobbler.Enabled() {
+				s.demand.Set(scrobblerID, 1)
+			}
+			scrobbler.Start()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			return scrobbler.Close()
+		},
+	})
+}
@@ -0,0 +1,441 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"spotify/protocols"
+	"spotify/services/history"
+	"spotify/services/scrobble"
+	"spotify/services/socket"
+	"spotify/services/spotify"
+	"spotify/services/tracing"
+	"spotify/services/webhook"
+
+	"github.com/knadh/koanf/v2"
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	protocols.UnimplementedSpotifyServer
+	spotify *spotify.SpotifyClient
+	demand  *demand
+	history *history.Store
+	plays   *plays
+	hooks   *webhook.Webhooks
+	// scrobbler follows the polled tracks like plays
+	scrobbler *scrobble.Scrobbler
+	// health is NOT_SERVING while Spotify can't be polled
+	health  *health.Server
+	serving bool
+
+	// every OnListen stream, fed by the poller
+	listeners   *socket.Pool[uint64, func(context.Context, *spotify.State, *spotify.State)]
+	listenerSeq atomic.Uint64
+	// every OnListen stream, fed on queue changes
+	queueListeners *socket.Pool[uint64, func(context.Context, *spotify.Queue)]
+	// every OnListen stream, fed with the artists enriched past the budget
+	artistsListeners *socket.Pool[uint64, func(context.Context, *spotify.Track)]
+
+	// IdlePollRate is used instead of the poll rate while no gateway has listeners
+	IdlePollRate time.Duration
+	// EnrichBudget is how long a track change may wait for its artists to be enriched
+	EnrichBudget time.Duration
+	// Location of the listening time buckets
+	Location *time.Location
+	// QueuePollRate is the cadence of the queue refreshes between track changes
+	QueuePollRate time.Duration
+	// ratesMu guards the poll rates, reloaded while polling
+	ratesMu sync.RWMutex
+	// queueRate tells the queue loop its rate changed
+	queueRate chan struct{}
+
+	state  *spotify.State
+	mu     sync.RWMutex
+	pollMu sync.Mutex
+
+	// last snapshot of the queue
+	queue    *spotify.Queue
+	queuedAt time.Time
+	queueMu  sync.Mutex
+}
+
+func New(client *spotify.SpotifyClient, store *history.Store, hooks *webhook.Webhooks, scrobbler *scrobble.Scrobbler, k *koanf.Koanf) (*Server, error) {
+	idlePollRate, queuePollRate := readPollRates(k)
+
+	enrichBudget := DefaultEnrichBudget
+	if k.Exists("spotify.artists.budget") {
+		enrichBudget = k.Duration("spotify.artists.budget")
+	}
+
+	playThreshold := DefaultPlayThreshold
+	if k.Exists("history.threshold") {
+		playThreshold = k.Duration("history.threshold")
+	}
+
+	// stats fall back to the time zone of the logs
+	timezone := k.String("stats.timezone")
+	if timezone == "" {
+		timezone = k.String("server.timezone")
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stats.timezone: %w", err)
+	}
+
+	// serving until a poll fails, unless the token was refused
+	serving := client.IsConnected()
+	return &Server{
+		spotify:          client,
+		demand:           newDemand(),
+		history:          store,
+		plays:            newPlays(store, playThreshold),
+		hooks:            hooks,
+		scrobbler:        scrobbler,
+		listeners:        socket.NewPool[uint64, func(context.Context, *spotify.State, *spotify.State)](),
+		queueListeners:   socket.NewPool[uint64, func(context.Context, *spotify.Queue)](),
+		artistsListeners: socket.NewPool[uint64, func(context.Context, *spotify.Track)](),
+		IdlePollRate:     idlePollRate,
+		EnrichBudget:     enrichBudget,
+		Location:         location,
+		QueuePollRate:    queuePollRate,
+		queueRate:        make(chan struct{}, 1),
+		health:           newHealth(serving),
+		serving:          serving,
+	}, nil
+}
+
+// readPollRates returns the idle and queue poll rates of k, in seconds
+func readPollRates(k *koanf.Koanf) (idle, queue time.Duration) {
+	idle, queue = DefaultIdlePollRate, DefaultQueuePollRate
+	if k.Exists("spotify.idle_poll_rate") {
+		idle = time.Duration(k.Int("spotify.idle_poll_rate"))
+	}
+	if k.Exists("spotify.queue_poll_rate") {
+		queue = time.Duration(k.Int("spotify.queue_poll_rate"))
+	}
+	return idle, queue
+}
+
+// setPollRates applies the poll rates of a reloaded configuration, waking the
+// pollers so they take them right away
+func (s *Server) setPollRates(k *koanf.Koanf) {
+	idle, queue := readPollRates(k)
+	s.ratesMu.Lock()
+	changed := idle != s.IdlePollRate || queue != s.QueuePollRate
+	s.IdlePollRate, s.QueuePollRate = idle, queue
+	s.ratesMu.Unlock()
+	if !changed {
+		return
+	}
+
+	s.demand.Wake()
+	select {
+	case s.queueRate <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Server) pollRates() (idle, queue time.Duration) {
+	s.ratesMu.RLock()
+	defer s.ratesMu.RUnlock()
+	return s.IdlePollRate, s.QueuePollRate
+}
+
+func (s *Server) setState(value *spotify.State) {
+	s.mu.Lock()
+	if s.state == nil {
+		s.state = value
+	} else {
+		*s.state = *value
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) getState() *spotify.State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+func (s *Server) isPlaying() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state != nil && s.state.Track.IsPlaying
+}
+
+func (s *Server) hasState() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state != nil
+}
+
+// GetTrack fetches the current state, falling back to the last polled one when
+// Spotify fails. Without any state it is Unavailable, the poller keeps trying.
+func (s *Server) GetTrack(ctx context.Context, req *protocols.Request) (*protocols.State, error) {
+	state, err := s.spotify.GetSpotifyStatus(ctx)
+	if err == nil {
+		return state.ToProto(), nil
+	}
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.state != nil {
+		return s.state.ToProto(), nil
+	}
+	return nil, status.Errorf(codes.Unavailable, "spotify unavailable: %v", err)
+}
+
+func (s *Server) OnListen(req *protocols.Request, stream grpc.ServerStreamingServer[protocols.Reponse]) error {
+	id := req.GetID()
+	// the gateway may have reconnected meanwhile, its demand is kept then
+	defer s.demand.Close(id, s.demand.Open(id))
+	subscribers.Inc()
+	defer subscribers.Dec()
+
+	// late enrichments are sent from other goroutines, streams aren't safe for concurrent sends
+	var sendMu sync.Mutex
+	closed := false
+	unsubscribe := s.subscribe(id, func(res *protocols.Reponse) {
+		sendMu.Lock()
+		if !closed {
+			stream.Send(res)
+		}
+		sendMu.Unlock()
+	})
+	defer func() {
+		unsubscribe()
+		sendMu.Lock()
+		closed = true
+		sendMu.Unlock()
+	}()
+
+	<-stream.Context().Done()
+	return nil
+}
+
+// subscribe feeds send with the events of every poll and queue change, the
+// same events gateways stream. Events carry the trace context of their poll.
+func (s *Server) subscribe(id string, send func(*protocols.Reponse)) (unsubscribe func()) {
+	listener := s.listenerSeq.Add(1)
+	s.listeners.Set(listener, func(ctx context.Context, state, oldState *spotify.State) {
+		if state != nil && oldState != nil {
+			trace := tracing.Carrier(ctx)
+			track, oldTrack, player := state.Track, oldState.Track, state.Player.ToProto()
+			// the poll enriched the artists already
+			if track.ID != oldTrack.ID {
+				send(&protocols.Reponse{ID: id, E: "CHANGE", Track: track.ToProto(), Player: player, Progress: nil, Trace: trace})
+			}
+
+			// a new track already carries its state
+			if track.ID == oldTrack.ID && track.IsPlaying != oldTrack.IsPlaying {
+				send(&protocols.Reponse{ID: id, E: "PLAYING", Track: track.ToProto(), Player: player, Progress: nil, Trace: trace})
+			}
+
+			if !state.Player.SameDevice(oldState.Player) {
+				send(&protocols.Reponse{
+					ID:       id,
+					E:        "DEVICE",
+					Track:    track.ToProto(),
+					Player:   player,
+					Progress: nil,
+					Trace:    trace,
+				})
+			}
+
+			if track.Timestamp != nil {
+				progress := int64(track.Timestamp.Progress)
+				send(&protocols.Reponse{ID: id, E: "PROGRESS", Track: nil, Progress: &progress, Trace: trace})
+			}
+		}
+	})
+	s.queueListeners.Set(listener, func(ctx context.Context, queue *spotify.Queue) {
+		send(&protocols.Reponse{ID: id, E: "QUEUE", Track: nil, Progress: nil, Queue: queue.ToProto(), Trace: tracing.Carrier(ctx)})
+	})
+	s.artistsListeners.Set(listener, func(ctx context.Context, track *spotify.Track) {
+		send(&protocols.Reponse{ID: id, E: "ARTISTS", Track: track.ToProto(), Progress: nil, Trace: tracing.Carrier(ctx)})
+	})
+
+	return func() {
+		s.listeners.Delete(listener)
+		s.queueListeners.Delete(listener)
+		s.artistsListeners.Delete(listener)
+	}
+}
+
+func (s *Server) SetDemand(_ context.Context, req *protocols.Demand) (*protocols.Demand, error) {
+	total := s.demand.Set(req.GetID(), req.GetListeners())
+	return &protocols.Demand{ID: req.GetID(), Listeners: total}, nil
+}
+
+func (s *Server) ListHistory(_ context.Context, req *protocols.HistoryRequest) (*protocols.HistoryResponse, error) {
+	query := history.Query{
+		Cursor: req.GetCursor(),
+		Limit:  int(req.GetLimit()),
+		Artist: req.GetArtist(),
+		Album:  req.GetAlbum(),
+	}
+	if req.From != nil {
+		query.From = time.UnixMilli(req.GetFrom())
+	}
+	if req.To != nil {
+		query.To = time.UnixMilli(req.GetTo())
+	}
+
+	plays, cursor, err := s.history.List(query)
+	if err != nil {
+		if errors.Is(err, history.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+
+	res := &protocols.HistoryResponse{Plays: make([]*protocols.Play, len(plays)), Cursor: cursor}
+	for i, play := range plays {
+		res.Plays[i] = play.ToProto()
+	}
+	return res, nil
+}
+
+func (s *Server) TopTracks(_ context.Context, req *protocols.StatsRequest) (*protocols.TopResponse, error) {
+	from, to := statsRange(req)
+	return topResponse(s.history.TopTracks(from, to, int(req.GetLimit())))
+}
+
+func (s *Server) TopArtists(_ context.Context, req *protocols.StatsRequest) (*protocols.TopResponse, error) {
+	from, to := statsRange(req)
+	return topResponse(s.history.TopArtists(from, to, int(req.GetLimit())))
+}
+
+func (s *Server) TopAlbums(_ context.Context, req *protocols.StatsRequest) (*protocols.TopResponse, error) {
+	from, to := statsRange(req)
+	return topResponse(s.history.TopAlbums(from, to, int(req.GetLimit())))
+}
+
+func (s *Server) ListeningTime(_ context.Context, req *protocols.StatsRequest) (*protocols.ListeningTimeResponse, error) {
+	bucket := req.GetBucket()
+	if bucket == "" {
+		bucket = history.BucketDay
+	}
+
+	from, to := statsRange(req)
+	lt, err := s.history.ListeningTime(from, to, bucket, s.Location)
+	if err != nil {
+		if errors.Is(err, history.ErrInvalidBucket) || errors.Is(err, history.ErrTooManyBuckets) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+	return lt.ToProto(), nil
+}
+
+func statsRange(req *protocols.StatsRequest) (from, to time.Time) {
+	if req.From != nil {
+		from = time.UnixMilli(req.GetFrom())
+	}
+	if req.To != nil {
+		to = time.UnixMilli(req.GetTo())
+	}
+	return from, to
+}
+
+func topResponse(items []*history.TopItem, err error) (*protocols.TopResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	res := &protocols.TopResponse{Items: make([]*protocols.TopItem, len(items))}
+	for i, item := range items {
+		res.Items[i] = item.ToProto()
+	}
+	return res, nil
+}
+
+func (s *Server) GetTopArtists(ctx context.Context, req *protocols.LibraryRequest) (*protocols.ArtistsResponse, error) {
+	if err := s.requireScope(spotifyauth.ScopeUserTopRead); err != nil {
+		return nil, err
+	}
+	page, err := s.spotify.GetTopArtists(ctx, libraryOptions(req))
+	if err != nil {
+		return nil, libraryError(err)
+	}
+	res := &protocols.ArtistsResponse{Artists: make([]*protocols.Artist, len(page.Items)), Total: int64(page.Total)}
+	for i, artist := range page.Items {
+		res.Artists[i] = artist.ToProto()
+	}
+	return res, nil
+}
+
+func (s *Server) GetTopTracks(ctx context.Context, req *protocols.LibraryRequest) (*protocols.TracksResponse, error) {
+	if err := s.requireScope(spotifyauth.ScopeUserTopRead); err != nil {
+		return nil, err
+	}
+	return tracksResponse(s.spotify.GetTopTracks(ctx, libraryOptions(req)))
+}
+
+func (s *Server) GetSavedTracks(ctx context.Context, req *protocols.LibraryRequest) (*protocols.TracksResponse, error) {
+	if err := s.requireScope(spotifyauth.ScopeUserLibraryRead); err != nil {
+		return nil, err
+	}
+	return tracksResponse(s.spotify.GetSavedTracks(ctx, libraryOptions(req)))
+}
+
+func (s *Server) GetPlaylists(ctx context.Context, req *protocols.LibraryRequest) (*protocols.PlaylistsResponse, error) {
+	if err := s.requireScope(spotifyauth.ScopePlaylistReadPrivate); err != nil {
+		return nil, err
+	}
+	page, err := s.spotify.GetPlaylists(ctx, libraryOptions(req))
+	if err != nil {
+		return nil, libraryError(err)
+	}
+	res := &protocols.PlaylistsResponse{Playlists: make([]*protocols.Playlist, len(page.Items)), Total: int64(page.Total)}
+	for i, playlist := range page.Items {
+		res.Playlists[i] = playlist.ToProto()
+	}
+	return res, nil
+}
+
+func libraryOptions(req *protocols.LibraryRequest) spotify.LibraryOptions {
+	return spotify.LibraryOptions{
+		TimeRange: req.GetTimeRange(),
+		Limit:     int(req.GetLimit()),
+		Offset:    int(req.GetOffset()),
+	}
+}
+
+// requireScope fails the call when the refresh token wasn't granted the scope,
+// Spotify would refuse it anyway
+func (s *Server) requireScope(scope string) error {
+	if !s.spotify.HasScope(scope) {
+		return status.Errorf(codes.FailedPrecondition, "the refresh token lacks the %s scope", scope)
+	}
+	return nil
+}
+
+func libraryError(err error) error {
+	if errors.Is(err, spotify.ErrInvalidTimeRange) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return controlError(err)
+}
+
+func tracksResponse(page *spotify.Page[*spotify.Track], err error) (*protocols.TracksResponse, error) {
+	if err != nil {
+		return nil, libraryError(err)
+	}
+	res := &protocols.TracksResponse{Tracks: make([]*protocols.Track, len(page.Items)), Total: int64(page.Total)}
+	for i, track := range page.Items {
+		res.Tracks[i] = track.ToProto()
+	}
+	return res, nil
+}
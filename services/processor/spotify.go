@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"spotify/services/spotify"
+	"spotify/services/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Longest an enrichment of artists may take, past the budget the track change
+// goes without them meanwhile
+const enrichTimeout = 10 * time.Second
+
+// pool polls Spotify and hands every new state to the listeners, a single
+// loop is shared by every gateway
+func (s *Server) pool(ctx context.Context) {
+	for ctx.Err() == nil {
+		if s.spotify.IsConnected() {
+			s.poll(ctx)
+		}
+		interval := s.pollInterval()
+		pollInterval.Set(interval.Seconds())
+		s.demand.Wait(ctx, interval)
+	}
+}
+
+// poll fetches the state once and hands it to the listeners. Player commands
+// poll too, so both are serialized to keep the listeners in order.
+func (s *Server) poll(ctx context.Context) (*spotify.State, error) {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+
+	// the events of the poll are traced back to it
+	ctx, span := tracing.Tracer.Start(ctx, "poll")
+	defer span.End()
+
+	state, err := s.spotify.GetSpotifyStatus(ctx)
+	// the poller backs off until Spotify answers again
+	s.setServing(err == nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.spotify.OnError()
+		return nil, err
+	}
+
+	track := state.Track
+	if track.IsPlaying || !s.hasState() {
+		s.spotify.ResetPollRate()
+	}
+	changed := false
+	if oldState := s.getState(); oldState != nil {
+		changed = oldState.Track.ID != track.ID
+		if changed {
+			s.enrich(ctx, track)
+		}
+		for _, onData := range s.listeners.All() {
+			onData(ctx, state, oldState)
+		}
+	}
+	s.plays.Observe(track)
+	s.scrobbler.Observe(track)
+	s.setState(state)
+
+	// the queue moves along with the track
+	if changed {
+		go func() {
+			if _, err := s.refreshQueue(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("error while fetching the queue: %v", err)
+			}
+		}()
+	}
+	return state, nil
+}
+
+// enrich fills the artists of a new track once for every listener, unless the
+// enrichment takes longer than the budget: then the track goes without them
+// and the enriched copy follows to the state and the artists listeners
+func (s *Server) enrich(ctx context.Context, track *spotify.Track) {
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	enriched := s.spotify.EnrichArtists(ctx, track.Artists)
+	timer := time.NewTimer(s.EnrichBudget)
+	defer timer.Stop()
+
+	select {
+	case track.Artists = <-enriched:
+		cancel()
+	case <-timer.C:
+		// the pool keeps using the track, work on a copy
+		late := *track
+		go func() {
+			defer cancel()
+			late.Artists = <-enriched
+			// after the poll of the track stored it
+			s.pollMu.Lock()
+			defer s.pollMu.Unlock()
+			s.setArtists(&late)
+			for _, onArtists := range s.artistsListeners.All() {
+				onArtists(ctx, &late)
+			}
+		}()
+	}
+}
+
+// setArtists updates the artists of the state, unless the track changed since
+func (s *Server) setArtists(track *spotify.Track) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil || s.state.Track == nil || s.state.Track.ID != track.ID {
+		return
+	}
+	// the pool may still read the previous track
+	enriched := *s.state.Track
+	enriched.Artists = track.Artists
+	s.state = &spotify.State{Track: &enriched, Player: s.state.Player}
+}
+
+// pollInterval falls back to the idle poll rate while nobody is listening and
+// nothing plays, the plays are recorded at the full rate
+func (s *Server) pollInterval() time.Duration {
+	rate := s.spotify.PollRate()
+	if idle, _ := s.pollRates(); s.demand.Total() == 0 && !s.isPlaying() && idle > rate {
+		return idle * time.Second
+	}
+	return rate * time.Second
+}
@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"context"
+
+	"spotify/protocols"
+	"spotify/services/config"
+	"spotify/services/spotify"
+
+	"github.com/knadh/koanf/v2"
+	"go.uber.org/fx"
+)
+
+// ID the webhooks subscribe and report their demand with
+const webhooksID = "webhooks"
+
+// ConfigureWebhooks feeds the webhooks with the events streamed to the
+// gateways, the endpoints follow the reloads of the configuration
+func ConfigureWebhooks(lc fx.Lifecycle, s *Server, w *config.Watcher) {
+	var unsubscribe func()
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			s.setWebhooksDemand()
+			unsubscribe = s.subscribe(webhooksID, func(res *protocols.Reponse) {
+				if msg := spotify.Dispatch(res); msg != nil {
+					s.hooks.Send(msg)
+				}
+			})
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			unsubscribe()
+			s.hooks.Close()
+			return nil
+		},
+	})
+
+	w.OnReload(func(k *koanf.Koanf) {
+		s.hooks.SetEndpoints(k)
+		s.setWebhooksDemand()
+	})
+}
+
+// setWebhooksDemand keeps the poller from idling while there are endpoints,
+// webhooks listen all the time
+func (s *Server) setWebhooksDemand() {
+	s.demand.Set(webhooksID, int64(min(s.hooks.Len(), 1)))
+}
+
+func (s *Server) GetWebhooks(_ context.Context, _ *protocols.Request) (*protocols.WebhooksResponse, error) {
+	status := s.hooks.Status()
+	res := &protocols.WebhooksResponse{Webhooks: make([]*protocols.Webhook, len(status))}
+	for i := range status {
+		res.Webhooks[i] = status[i].ToProto()
+	}
+	return res, nil
+}
@@ -0,0 +1,211 @@
+package scrobble
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/knadh/koanf/v2"
+)
+
+const DefaultLastFMURL = "https://ws.audioscrobbler.com/2.0/"
+
+const (
+	// Last.fm errors of the scrobbles themselves, retrying won't help
+	lastfmInvalidParameters = 6
+	lastfmInvalidResource   = 7
+	// Last.fm error of an expired or revoked session key
+	lastfmInvalidSession = 9
+)
+
+// LastFM scrobbles to the Last.fm API, or any API compatible with it
+type LastFM struct {
+	client *http.Client
+	// URL of the API
+	URL string
+
+	apiKey    string
+	apiSecret string
+	username  string
+	password  string
+
+	mu         sync.Mutex
+	sessionKey string
+}
+
+func NewLastFM(k *koanf.Koanf) (*LastFM, error) {
+	lastfm := &LastFM{
+		client:     &http.Client{},
+		URL:        k.String("scrobble.lastfm.url"),
+		apiKey:     k.String("scrobble.lastfm.api_key"),
+		apiSecret:  k.String("scrobble.lastfm.api_secret"),
+		username:   k.String("scrobble.lastfm.username"),
+		password:   k.String("scrobble.lastfm.password"),
+		sessionKey: k.String("scrobble.lastfm.session_key"),
+	}
+	if lastfm.URL == "" {
+		lastfm.URL = DefaultLastFMURL
+	}
+	if lastfm.apiSecret == "" {
+		return nil, errors.New("scrobble: scrobble.lastfm.api_secret is required")
+	}
+	if lastfm.sessionKey == "" && (lastfm.username == "" || lastfm.password == "") {
+		return nil, errors.New("scrobble: scrobble.lastfm.session_key or scrobble.lastfm.username and password are required")
+	}
+	return lastfm, nil
+}
+
+func (lastfm *LastFM) Name() string {
+	return "lastfm"
+}
+
+func (lastfm *LastFM) NowPlaying(ctx context.Context, scrobble *Scrobble) error {
+	return lastfm.call(ctx, "track.updateNowPlaying", lastfm.params(scrobble), nil)
+}
+
+func (lastfm *LastFM) Submit(ctx context.Context, scrobble *Scrobble) error {
+	params := lastfm.params(scrobble)
+	params.Set("timestamp", strconv.FormatInt(scrobble.PlayedAt.Unix(), 10))
+	return lastfm.call(ctx, "track.scrobble", params, nil)
+}
+
+func (lastfm *LastFM) params(scrobble *Scrobble) url.Values {
+	params := url.Values{}
+	params.Set("track", scrobble.Track)
+	if len(scrobble.Artists) > 0 {
+		params.Set("artist", scrobble.Artists[0])
+	}
+	if scrobble.Album != "" {
+		params.Set("album", scrobble.Album)
+	}
+	if scrobble.Duration > 0 {
+		params.Set("duration", strconv.Itoa(int(scrobble.Duration.Seconds())))
+	}
+	if scrobble.TrackNumber > 0 {
+		params.Set("trackNumber", strconv.Itoa(scrobble.TrackNumber))
+	}
+	return params
+}
+
+// session returns the session key, requesting it with the username and
+// password the first time when it isn't configured
+func (lastfm *LastFM) session(ctx context.Context) (string, error) {
+	lastfm.mu.Lock()
+	defer lastfm.mu.Unlock()
+	if lastfm.sessionKey != "" {
+		return lastfm.sessionKey, nil
+	}
+
+	var res struct {
+		Session struct {
+			Key string `json:"key"`
+		} `json:"session"`
+	}
+	params := url.Values{}
+	params.Set("username", lastfm.username)
+	params.Set("password", lastfm.password)
+	if err := lastfm.post(ctx, "auth.getMobileSession", params, &res); err != nil {
+		return "", err
+	}
+	lastfm.sessionKey = res.Session.Key
+	return lastfm.sessionKey, nil
+}
+
+// call runs an authenticated method
+func (lastfm *LastFM) call(ctx context.Context, method string, params url.Values, v any) error {
+	sessionKey, err := lastfm.session(ctx)
+	if err != nil {
+		return err
+	}
+	params.Set("sk", sessionKey)
+
+	err = lastfm.post(ctx, method, params, v)
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) && serviceErr.Code == lastfmInvalidSession && lastfm.username != "" {
+		// requested again on the next call
+		lastfm.mu.Lock()
+		lastfm.sessionKey = ""
+		lastfm.mu.Unlock()
+	}
+	return err
+}
+
+func (lastfm *LastFM) post(ctx context.Context, method string, params url.Values, v any) error {
+	params.Set("method", method)
+	params.Set("api_key", lastfm.apiKey)
+	params.Set("api_sig", lastfm.sign(params))
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lastfm.URL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "Spotify-Server")
+
+	res, err := lastfm.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	raw := json.RawMessage{}
+	if err := json.NewDecoder(res.Body).Decode(&raw); err == nil {
+		json.Unmarshal(raw, &body)
+	}
+	if body.Error != 0 {
+		// authentication errors are retried too, the scrobbles go through once
+		// the configuration is fixed
+		return &Error{
+			Service:   lastfm.Name(),
+			Code:      body.Error,
+			Message:   body.Message,
+			Temporary: body.Error != lastfmInvalidParameters && body.Error != lastfmInvalidResource,
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &Error{
+			Service:   lastfm.Name(),
+			Code:      res.StatusCode,
+			Message:   fmt.Sprintf("unexpected status %s", res.Status),
+			Temporary: res.StatusCode != http.StatusBadRequest,
+		}
+	}
+	if v != nil {
+		return json.Unmarshal(raw, v)
+	}
+	return nil
+}
+
+// sign returns the md5 of every parameter (name and value) sorted by name,
+// followed by the secret
+func (lastfm *LastFM) sign(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var sig strings.Builder
+	for _, key := range keys {
+		sig.WriteString(key)
+		sig.WriteString(params.Get(key))
+	}
+	sig.WriteString(lastfm.apiSecret)
+
+	sum := md5.Sum([]byte(sig.String()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,236 @@
+package scrobble
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+func newKoanf(t *testing.T, conf map[string]any) *koanf.Koanf {
+	t.Helper()
+	k := koanf.New(".")
+	if err := k.Load(confmap.Provider(conf, "."), nil); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// lastfmServer answers every method with answer, recording the requests
+type lastfmServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func newLastFMServer(t *testing.T, answer func(form url.Values) (int, string)) *lastfmServer {
+	t.Helper()
+	server := &lastfmServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parsing the request: %v", err)
+		}
+		server.mu.Lock()
+		server.requests = append(server.requests, r.PostForm)
+		server.mu.Unlock()
+
+		status, body := answer(r.PostForm)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *lastfmServer) methods() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	methods := make([]string, len(server.requests))
+	for i, form := range server.requests {
+		methods[i] = form.Get("method")
+	}
+	return methods
+}
+
+func newTestLastFM(t *testing.T, server *lastfmServer, conf map[string]any) *LastFM {
+	t.Helper()
+	conf["scrobble.lastfm.url"] = server.URL
+	conf["scrobble.lastfm.api_key"] = "key"
+	conf["scrobble.lastfm.api_secret"] = "secret"
+	lastfm, err := NewLastFM(newKoanf(t, conf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lastfm
+}
+
+var testScrobble = &Scrobble{
+	ID:       "track",
+	Track:    "Title",
+	Artists:  []string{"Artist", "Other"},
+	Album:    "Album",
+	Duration: 3 * time.Minute,
+	PlayedAt: time.Unix(1700000000, 0),
+}
+
+func TestLastFMSign(t *testing.T) {
+	lastfm := &LastFM{apiSecret: "secret"}
+	params := url.Values{}
+	params.Set("username", "someone")
+	params.Set("password", "hunter2")
+	params.Set("method", "auth.getMobileSession")
+	params.Set("api_key", "key")
+
+	// md5 of "api_keykeymethodauth.getMobileSessionpasswordhunter2usernamesomeonesecret"
+	if sig := lastfm.sign(params); sig != "3945b7a2588406883aa403227fc25f07" {
+		t.Fatalf("got the signature %s", sig)
+	}
+}
+
+func TestLastFMSubmit(t *testing.T) {
+	server := newLastFMServer(t, func(form url.Values) (int, string) {
+		if form.Get("method") == "auth.getMobileSession" {
+			return http.StatusOK, `{"session":{"name":"someone","key":"session"}}`
+		}
+		return http.StatusOK, `{"scrobbles":{}}`
+	})
+	lastfm := newTestLastFM(t, server, map[string]any{
+		"scrobble.lastfm.username": "someone",
+		"scrobble.lastfm.password": "hunter2",
+	})
+
+	if err := lastfm.Submit(context.Background(), testScrobble); err != nil {
+		t.Fatal(err)
+	}
+	if err := lastfm.Submit(context.Background(), testScrobble); err != nil {
+		t.Fatal(err)
+	}
+
+	// the session is requested once
+	if methods := server.methods(); len(methods) != 3 || methods[0] != "auth.getMobileSession" || methods[1] != "track.scrobble" || methods[2] != "track.scrobble" {
+		t.Fatalf("got the calls %v", methods)
+	}
+
+	server.mu.Lock()
+	form := server.requests[1]
+	server.mu.Unlock()
+	want := map[string]string{
+		"api_key":   "key",
+		"sk":        "session",
+		"track":     "Title",
+		"artist":    "Artist",
+		"album":     "Album",
+		"duration":  "180",
+		"timestamp": "1700000000",
+		"format":    "json",
+	}
+	for key, value := range want {
+		if form.Get(key) != value {
+			t.Errorf("%s is %q, want %q", key, form.Get(key), value)
+		}
+	}
+
+	// the signature covers every parameter but format and itself
+	signed := url.Values{}
+	for key, values := range form {
+		if key != "format" && key != "api_sig" {
+			signed[key] = values
+		}
+	}
+	if sig := (&LastFM{apiSecret: "secret"}).sign(signed); form.Get("api_sig") != sig {
+		t.Errorf("api_sig is %s, want %s", form.Get("api_sig"), sig)
+	}
+}
+
+func TestLastFMErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		code      int
+		temporary bool
+	}{
+		{"invalid parameters", http.StatusOK, `{"error":6,"message":"Invalid parameters"}`, 6, false},
+		{"invalid resource", http.StatusBadRequest, `{"error":7,"message":"Invalid resource specified"}`, 7, false},
+		{"invalid session", http.StatusForbidden, `{"error":9,"message":"Invalid session key"}`, 9, true},
+		{"service offline", http.StatusServiceUnavailable, `{"error":11,"message":"Service Offline"}`, 11, true},
+		{"rate limit", http.StatusTooManyRequests, `{"error":29,"message":"Rate limit exceeded"}`, 29, true},
+		{"bad request without body", http.StatusBadRequest, ``, http.StatusBadRequest, false},
+		{"server error without body", http.StatusBadGateway, `<html>`, http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newLastFMServer(t, func(url.Values) (int, string) {
+				return tt.status, tt.body
+			})
+			lastfm := newTestLastFM(t, server, map[string]any{"scrobble.lastfm.session_key": "session"})
+
+			err := lastfm.Submit(context.Background(), testScrobble)
+			var serviceErr *Error
+			if !errors.As(err, &serviceErr) {
+				t.Fatalf("got %v, want an *Error", err)
+			}
+			if serviceErr.Code != tt.code || serviceErr.Temporary != tt.temporary {
+				t.Fatalf("got code %d temporary %t, want code %d temporary %t", serviceErr.Code, serviceErr.Temporary, tt.code, tt.temporary)
+			}
+		})
+	}
+}
+
+func TestLastFMInvalidSession(t *testing.T) {
+	var sessions atomic.Int32
+	server := newLastFMServer(t, func(form url.Values) (int, string) {
+		if form.Get("method") == "auth.getMobileSession" {
+			if sessions.Add(1) == 1 {
+				return http.StatusOK, `{"session":{"key":"revoked"}}`
+			}
+			return http.StatusOK, `{"session":{"key":"renewed"}}`
+		}
+		if form.Get("sk") == "revoked" {
+			return http.StatusForbidden, `{"error":9,"message":"Invalid session key"}`
+		}
+		return http.StatusOK, `{}`
+	})
+	lastfm := newTestLastFM(t, server, map[string]any{
+		"scrobble.lastfm.username": "someone",
+		"scrobble.lastfm.password": "hunter2",
+	})
+
+	if err := lastfm.Submit(context.Background(), testScrobble); err == nil {
+		t.Fatal("the revoked session succeeded")
+	}
+	// the session is requested again
+	if err := lastfm.Submit(context.Background(), testScrobble); err != nil {
+		t.Fatal(err)
+	}
+	if n := sessions.Load(); n != 2 {
+		t.Fatalf("the session was requested %d times, want 2", n)
+	}
+}
+
+func TestLastFMInvalidSessionKeptWithoutPassword(t *testing.T) {
+	server := newLastFMServer(t, func(url.Values) (int, string) {
+		return http.StatusForbidden, `{"error":9,"message":"Invalid session key"}`
+	})
+	lastfm := newTestLastFM(t, server, map[string]any{"scrobble.lastfm.session_key": "session"})
+
+	for range 2 {
+		if err := lastfm.Submit(context.Background(), testScrobble); err == nil {
+			t.Fatal("the invalid session succeeded")
+		}
+	}
+	// without password the configured session is the only one
+	for _, method := range server.methods() {
+		if method != "track.scrobble" {
+			t.Fatalf("got the calls %v", server.methods())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package scrobble
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/knadh/koanf/v2"
+)
+
+const DefaultListenBrainzURL = "https://api.listenbrainz.org"
+
+// ListenBrainz submits listens to the ListenBrainz API, either the public
+// instance or a self-hosted one
+type ListenBrainz struct {
+	client *http.Client
+	// URL of the API, without the version
+	URL string
+
+	token string
+}
+
+type listen struct {
+	ListenedAt int64         `json:"listened_at,omitempty"`
+	Metadata   trackMetadata `json:"track_metadata"`
+}
+
+type trackMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo map[string]any `json:"additional_info"`
+}
+
+func NewListenBrainz(k *koanf.Koanf) *ListenBrainz {
+	listenbrainz := &ListenBrainz{
+		client: &http.Client{},
+		URL:    strings.TrimSuffix(k.String("scrobble.listenbrainz.url"), "/"),
+		token:  k.String("scrobble.listenbrainz.token"),
+	}
+	if listenbrainz.URL == "" {
+		listenbrainz.URL = DefaultListenBrainzURL
+	}
+	return listenbrainz
+}
+
+func (listenbrainz *ListenBrainz) Name() string {
+	return "listenbrainz"
+}
+
+func (listenbrainz *ListenBrainz) NowPlaying(ctx context.Context, scrobble *Scrobble) error {
+	return listenbrainz.submit(ctx, "playing_now", listen{Metadata: listenbrainz.metadata(scrobble)})
+}
+
+func (listenbrainz *ListenBrainz) Submit(ctx context.Context, scrobble *Scrobble) error {
+	return listenbrainz.submit(ctx, "single", listen{ListenedAt: scrobble.PlayedAt.Unix(), Metadata: listenbrainz.metadata(scrobble)})
+}
+
+func (listenbrainz *ListenBrainz) metadata(scrobble *Scrobble) trackMetadata {
+	info := map[string]any{
+		"media_player":      "Spotify",
+		"submission_client": "Spotify-Server",
+		"music_service":     "spotify.com",
+		"spotify_id":        scrobble.URL,
+		"origin_url":        scrobble.URL,
+		"duration_ms":       scrobble.Duration.Milliseconds(),
+		"artist_names":      scrobble.Artists,
+	}
+	if scrobble.TrackNumber > 0 {
+		info["tracknumber"] = scrobble.TrackNumber
+	}
+	if scrobble.ISRC != "" {
+		info["isrc"] = scrobble.ISRC
+	}
+	return trackMetadata{
+		ArtistName:     strings.Join(scrobble.Artists, ", "),
+		TrackName:      scrobble.Track,
+		ReleaseName:    scrobble.Album,
+		AdditionalInfo: info,
+	}
+}
+
+func (listenbrainz *ListenBrainz) submit(ctx context.Context, listenType string, item listen) error {
+	body, err := json.Marshal(map[string]any{"listen_type": listenType, "payload": []listen{item}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, listenbrainz.URL+"/1/submit-listens", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+listenbrainz.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Spotify-Server")
+
+	res, err := listenbrainz.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	var answer struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(res.Body).Decode(&answer)
+	if answer.Error == "" {
+		answer.Error = fmt.Sprintf("unexpected status %s", res.Status)
+	}
+	// only malformed listens are dropped, authentication errors go through
+	// once the token is fixed
+	return &Error{
+		Service:   listenbrainz.Name(),
+		Code:      res.StatusCode,
+		Message:   answer.Error,
+		Temporary: res.StatusCode != http.StatusBadRequest,
+	}
+}
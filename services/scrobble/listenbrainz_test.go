@@ -0,0 +1,86 @@
+package scrobble
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestListenBrainzSubmit(t *testing.T) {
+	var got struct {
+		ListenType string   `json:"listen_type"`
+		Payload    []listen `json:"payload"`
+	}
+	var mu sync.Mutex
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding the listens: %v", err)
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	listenbrainz := NewListenBrainz(newKoanf(t, map[string]any{
+		"scrobble.listenbrainz.url":   server.URL + "/",
+		"scrobble.listenbrainz.token": "token",
+	}))
+	if err := listenbrainz.Submit(context.Background(), testScrobble); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/1/submit-listens" || auth != "Token token" {
+		t.Fatalf("got %s with %q", path, auth)
+	}
+	if got.ListenType != "single" || len(got.Payload) != 1 {
+		t.Fatalf("got the %s listens %+v", got.ListenType, got.Payload)
+	}
+	item := got.Payload[0]
+	if item.ListenedAt != testScrobble.PlayedAt.Unix() || item.Metadata.TrackName != "Title" || item.Metadata.ArtistName != "Artist, Other" || item.Metadata.ReleaseName != "Album" {
+		t.Fatalf("got the listen %+v", item)
+	}
+}
+
+func TestListenBrainzErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		message   string
+		temporary bool
+	}{
+		{"malformed listen", http.StatusBadRequest, `{"code":400,"error":"JSON document does not contain any listens"}`, "JSON document does not contain any listens", false},
+		{"invalid token", http.StatusUnauthorized, `{"code":401,"error":"Invalid authorization token."}`, "Invalid authorization token.", true},
+		{"rate limit", http.StatusTooManyRequests, ``, "unexpected status 429 Too Many Requests", true},
+		{"server error", http.StatusInternalServerError, `<html>`, "unexpected status 500 Internal Server Error", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			listenbrainz := NewListenBrainz(newKoanf(t, map[string]any{"scrobble.listenbrainz.url": server.URL}))
+
+			err := listenbrainz.Submit(context.Background(), testScrobble)
+			var serviceErr *Error
+			if !errors.As(err, &serviceErr) {
+				t.Fatalf("got %v, want an *Error", err)
+			}
+			if serviceErr.Code != tt.status || serviceErr.Message != tt.message || serviceErr.Temporary != tt.temporary {
+				t.Fatalf("got %d %q temporary %t, want %d %q temporary %t", serviceErr.Code, serviceErr.Message, serviceErr.Temporary, tt.status, tt.message, tt.temporary)
+			}
+		})
+	}
+}
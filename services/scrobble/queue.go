@@ -0,0 +1,112 @@
+package scrobble
+
+import (
+	"encoding/binary"
+	"log"
+	"time"
+
+	"github.com/goccy/go-json"
+	bolt "go.etcd.io/bbolt"
+)
+
+// deadSuffix names the bucket of the entries of a service that can't be decoded
+const deadSuffix = ".dead"
+
+// Queue keeps the scrobbles pending for each service in a bbolt database, in
+// the order they were pushed
+type Queue struct {
+	db *bolt.DB
+}
+
+func OpenQueue(path string) (*Queue, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &Queue{db: db}, nil
+}
+
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Push appends the scrobble to the queue of the service
+func (q *Queue) Push(service string, scrobble *Scrobble) error {
+	value, err := json.Marshal(scrobble)
+	if err != nil {
+		return err
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(service))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(binary.BigEndian.AppendUint64(nil, seq), value)
+	})
+}
+
+// Peek returns the oldest scrobble of the service along with its key, nil
+// when the queue is empty. Entries that can't be decoded would block the
+// queue, they are moved to the dead bucket of the service and logged.
+func (q *Queue) Peek(service string) ([]byte, *Scrobble, error) {
+	var key []byte
+	var scrobble *Scrobble
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(service))
+		if bucket == nil {
+			return nil
+		}
+		for k, v := bucket.Cursor().First(); k != nil; k, v = bucket.Cursor().First() {
+			entry := &Scrobble{}
+			err := json.Unmarshal(v, entry)
+			if err == nil {
+				key, scrobble = append([]byte(nil), k...), entry
+				return nil
+			}
+
+			log.Printf("corrupt scrobble %x moved to the dead bucket of %s: %v", k, service, err)
+			dead, err := tx.CreateBucketIfNotExists([]byte(service + deadSuffix))
+			if err != nil {
+				return err
+			}
+			// the page of the entry is rewritten by the delete
+			if err := dead.Put(append([]byte(nil), k...), append([]byte(nil), v...)); err != nil {
+				return err
+			}
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, scrobble, nil
+}
+
+// Delete removes the scrobble of the key from the queue of the service
+func (q *Queue) Delete(service string, key []byte) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket([]byte(service)); bucket != nil {
+			return bucket.Delete(key)
+		}
+		return nil
+	})
+}
+
+// Len returns the amount of scrobbles pending for the service
+func (q *Queue) Len(service string) (int, error) {
+	n := 0
+	err := q.db.View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket([]byte(service)); bucket != nil {
+			n = bucket.Stats().KeyN
+		}
+		return nil
+	})
+	return n, err
+}
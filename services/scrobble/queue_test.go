@@ -0,0 +1,121 @@
+package scrobble
+
+import (
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openQueue(t *testing.T, path string) *Queue {
+	t.Helper()
+	queue, err := OpenQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { queue.Close() })
+	return queue
+}
+
+// peek returns the ID of the oldest scrobble, empty when there is none
+func peek(t *testing.T, queue *Queue, service string) ([]byte, string) {
+	t.Helper()
+	key, scrobble, err := queue.Peek(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scrobble == nil {
+		return nil, ""
+	}
+	return key, scrobble.ID
+}
+
+func TestQueueOrder(t *testing.T) {
+	queue := openQueue(t, filepath.Join(t.TempDir(), "scrobbles.db"))
+	for _, id := range []string{"first", "second"} {
+		if err := queue.Push("lastfm", &Scrobble{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Push("listenbrainz", &Scrobble{ID: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// peeking again returns the same scrobble until it is deleted
+	for _, want := range []string{"first", "first"} {
+		if _, id := peek(t, queue, "lastfm"); id != want {
+			t.Fatalf("peeked %q, want %q", id, want)
+		}
+	}
+	for _, want := range []string{"first", "second", ""} {
+		key, id := peek(t, queue, "lastfm")
+		if id != want {
+			t.Fatalf("peeked %q, want %q", id, want)
+		}
+		if key != nil {
+			if err := queue.Delete("lastfm", key); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if n, err := queue.Len("listenbrainz"); err != nil || n != 1 {
+		t.Fatalf("the other service has %d scrobbles (%v), want 1", n, err)
+	}
+	if _, id := peek(t, queue, "unknown"); id != "" {
+		t.Fatalf("peeked %q of a service without queue", id)
+	}
+}
+
+func TestQueueDeadBucket(t *testing.T) {
+	queue := openQueue(t, filepath.Join(t.TempDir(), "scrobbles.db"))
+	if err := queue.Push("lastfm", &Scrobble{ID: "before"}); err != nil {
+		t.Fatal(err)
+	}
+	key, _ := peek(t, queue, "lastfm")
+	// a corrupt entry in front of the next one
+	if err := queue.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("lastfm")).Put(key, []byte("{corrupt"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Push("lastfm", &Scrobble{ID: "after"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, id := peek(t, queue, "lastfm"); id != "after" {
+		t.Fatalf("peeked %q, want the scrobble after the corrupt one", id)
+	}
+	if n, err := queue.Len("lastfm"); err != nil || n != 1 {
+		t.Fatalf("the queue has %d scrobbles (%v), want 1", n, err)
+	}
+	var dead []byte
+	queue.db.View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket([]byte("lastfm" + deadSuffix)); bucket != nil {
+			dead = bucket.Get(key)
+		}
+		return nil
+	})
+	if string(dead) != "{corrupt" {
+		t.Fatalf("the dead bucket has %q", dead)
+	}
+}
+
+func TestQueueReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scrobbles.db")
+	queue, err := OpenQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Push("lastfm", &Scrobble{ID: "pending", Track: "Title"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, scrobble, err := openQueue(t, path).Peek("lastfm")
+	if err != nil || scrobble == nil || scrobble.ID != "pending" || scrobble.Track != "Title" {
+		t.Fatalf("got %+v (%v) after reopening, want the pending scrobble", scrobble, err)
+	}
+}
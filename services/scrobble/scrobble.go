@@ -0,0 +1,305 @@
+package scrobble
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"spotify/services/spotify"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	DefaultQueuePath     = "scrobbles.db"
+	DefaultRetryInterval = time.Minute
+	DefaultTimeout       = 10 * time.Second
+
+	// Tracks shorter than this are never scrobbled
+	minDuration = 30 * time.Second
+	// Listened time to scrobble a track, shorter tracks need half their duration
+	scrobbleThreshold = 4 * time.Minute
+	// Going back further than this on the same track starts a new scrobble
+	replayTolerance = 5 * time.Second
+)
+
+// Scrobble is a track listened past the threshold, or being listened for the
+// now playing updates
+type Scrobble struct {
+	// Spotify ID of the track
+	ID string `json:"id"`
+	// Title of the track
+	Track string `json:"track"`
+	// Names of the artists, the first one is the main artist
+	Artists []string `json:"artists"`
+	// Name of the album
+	Album string `json:"album,omitempty"`
+	// Duration of the track
+	Duration time.Duration `json:"duration"`
+	// Position of the track in the album
+	TrackNumber int `json:"track_number,omitempty"`
+	// ISRC of the track, if known
+	ISRC string `json:"isrc,omitempty"`
+	// Spotify URL of the track
+	URL string `json:"url,omitempty"`
+	// When the play started
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// Service is a scrobbling service, eg: Last.fm or ListenBrainz
+type Service interface {
+	// Name identifies the service in the logs and the queue
+	Name() string
+	// NowPlaying tells the service the track started playing
+	NowPlaying(ctx context.Context, scrobble *Scrobble) error
+	// Submit scrobbles the track
+	Submit(ctx context.Context, scrobble *Scrobble) error
+}
+
+// Error is an error answered by a service. Temporary errors are retried,
+// others drop the scrobble.
+type Error struct {
+	Service   string
+	Code      int
+	Message   string
+	Temporary bool
+}
+
+func (e *Error) Error() string {
+	return e.Service + ": " + e.Message
+}
+
+// Scrobbler follows the polled tracks, announcing them as now playing and
+// scrobbling the ones listened past half their duration or 4 minutes. Scrobbles
+// go through a durable queue, so the ones failing are retried once the service
+// recovers, even after a restart.
+type Scrobbler struct {
+	services []Service
+	queue    *Queue
+
+	// RetryInterval is the wait between retries of the queued scrobbles
+	RetryInterval time.Duration
+
+	current   *Scrobble
+	progress  time.Duration
+	playing   bool
+	submitted bool
+
+	// latest track to announce as now playing
+	announce chan Scrobble
+	flush    chan struct{}
+	ctx      context.Context
+	stop     context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func New(k *koanf.Koanf) (*Scrobbler, error) {
+	services := []Service{}
+	if k.String("scrobble.lastfm.api_key") != "" {
+		lastfm, err := NewLastFM(k)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, lastfm)
+	}
+	if k.String("scrobble.listenbrainz.token") != "" {
+		services = append(services, NewListenBrainz(k))
+	}
+
+	retryInterval := DefaultRetryInterval
+	if k.Exists("scrobble.retry_interval") {
+		retryInterval = k.Duration("scrobble.retry_interval")
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	s := &Scrobbler{
+		services:      services,
+		RetryInterval: retryInterval,
+		announce:      make(chan Scrobble, 1),
+		flush:         make(chan struct{}, 1),
+		ctx:           ctx,
+		stop:          stop,
+	}
+	if len(services) == 0 {
+		return s, nil
+	}
+
+	path := k.String("scrobble.queue")
+	if path == "" {
+		path = DefaultQueuePath
+	}
+	queue, err := OpenQueue(path)
+	if err != nil {
+		return nil, err
+	}
+	s.queue = queue
+	return s, nil
+}
+
+// Enabled reports whether a service is configured
+func (s *Scrobbler) Enabled() bool {
+	return len(s.services) > 0
+}
+
+// Start submits the scrobbles queued, retrying the failed ones until Close
+func (s *Scrobbler) Start() {
+	if len(s.services) == 0 {
+		return
+	}
+	for _, service := range s.services {
+		if pending, err := s.queue.Len(service.Name()); err == nil && pending > 0 {
+			log.Printf("%d scrobbles pending for %s", pending, service.Name())
+		}
+	}
+	s.wg.Add(2)
+	go s.run()
+	go s.nowPlaying()
+}
+
+// Close stops the submissions, the scrobbles left are kept for the next start
+func (s *Scrobbler) Close() error {
+	s.stop()
+	s.wg.Wait()
+	if s.queue == nil {
+		return nil
+	}
+	return s.queue.Close()
+}
+
+// Observe must be called with every polled track, from a single goroutine
+func (s *Scrobbler) Observe(track *spotify.Track) {
+	// nothing playing, ads and episodes aren't scrobbled
+	if len(s.services) == 0 || track == nil || track.Timestamp == nil || track.ID == "" || track.Type == spotify.EpisodeItem {
+		return
+	}
+
+	progress := time.Duration(track.Timestamp.Progress) * time.Millisecond
+	if s.current == nil || s.current.ID != string(track.ID) || progress+replayTolerance < s.progress {
+		s.current = newScrobble(track, time.Now().Add(-progress))
+		s.playing, s.submitted = false, false
+	}
+	s.progress = progress
+
+	// announced when it starts and again when resumed
+	if track.IsPlaying && !s.playing {
+		// keep only the latest track pending to be announced
+		select {
+		case <-s.announce:
+		default:
+		}
+		s.announce <- *s.current
+	}
+	s.playing = track.IsPlaying
+
+	duration := time.Duration(track.Timestamp.Duration) * time.Millisecond
+	if !s.submitted && duration > minDuration && progress >= min(duration/2, scrobbleThreshold) {
+		s.submitted = true
+		s.submit(s.current)
+	}
+}
+
+// nowPlaying announces the tracks one at a time, so they arrive in order.
+// They aren't retried, the services expire them anyway.
+func (s *Scrobbler) nowPlaying() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case scrobble := <-s.announce:
+			for _, service := range s.services {
+				ctx, cancel := context.WithTimeout(s.ctx, DefaultTimeout)
+				if err := service.NowPlaying(ctx, &scrobble); err != nil {
+					log.Printf("error while updating now playing on %s: %v", service.Name(), err)
+				}
+				cancel()
+			}
+		}
+	}
+}
+
+// submit queues the scrobble for every service and wakes the worker up
+func (s *Scrobbler) submit(scrobble *Scrobble) {
+	for _, service := range s.services {
+		if err := s.queue.Push(service.Name(), scrobble); err != nil {
+			log.Printf("error while queueing scrobble for %s: %v", service.Name(), err)
+		}
+	}
+	select {
+	case s.flush <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scrobbler) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, service := range s.services {
+			s.flushService(service)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.flush:
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushService submits the scrobbles queued for the service in order, until
+// the queue is empty or the service fails
+func (s *Scrobbler) flushService(service Service) {
+	for s.ctx.Err() == nil {
+		key, scrobble, err := s.queue.Peek(service.Name())
+		if err != nil {
+			log.Printf("error while reading scrobble queue of %s: %v", service.Name(), err)
+			return
+		}
+		if scrobble == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, DefaultTimeout)
+		err = service.Submit(ctx, scrobble)
+		cancel()
+		if err != nil {
+			var serviceErr *Error
+			if !errors.As(err, &serviceErr) || serviceErr.Temporary {
+				log.Printf("error while scrobbling to %s, retrying in %s: %v", service.Name(), s.RetryInterval, err)
+				return
+			}
+			log.Printf("scrobble of %s to %s dropped: %v", scrobble.Track, service.Name(), err)
+		}
+
+		if err := s.queue.Delete(service.Name(), key); err != nil {
+			log.Printf("error while removing scrobble from the queue of %s: %v", service.Name(), err)
+			return
+		}
+	}
+}
+
+func newScrobble(track *spotify.Track, playedAt time.Time) *Scrobble {
+	scrobble := &Scrobble{
+		ID:          string(track.ID),
+		Track:       track.Title,
+		Artists:     make([]string, len(track.Artists)),
+		Duration:    time.Duration(track.Timestamp.Duration) * time.Millisecond,
+		TrackNumber: int(track.TrackNumber),
+		ISRC:        track.ISRC,
+		URL:         track.URL,
+		PlayedAt:    playedAt.Truncate(time.Second),
+	}
+	for i, artist := range track.Artists {
+		scrobble.Artists[i] = artist.Name
+	}
+	if track.Album != nil {
+		scrobble.Album = track.Album.Name
+	}
+	return scrobble
+}
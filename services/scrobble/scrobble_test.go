@@ -0,0 +1,208 @@
+package scrobble
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"spotify/services/spotify"
+
+	sm "github.com/zmb3/spotify/v2"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeService records the scrobbles submitted, failing with the errors queued
+// or all the time while down
+type fakeService struct {
+	mu        sync.Mutex
+	down      bool
+	errs      []error
+	submitted []string
+}
+
+func (service *fakeService) Name() string {
+	return "fake"
+}
+
+func (service *fakeService) NowPlaying(context.Context, *Scrobble) error {
+	return nil
+}
+
+func (service *fakeService) Submit(_ context.Context, scrobble *Scrobble) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.down {
+		return &Error{Service: service.Name(), Message: "down", Temporary: true}
+	}
+	if len(service.errs) > 0 {
+		err := service.errs[0]
+		service.errs = service.errs[1:]
+		return err
+	}
+	service.submitted = append(service.submitted, scrobble.ID)
+	return nil
+}
+
+func (service *fakeService) Submitted() []string {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return append([]string(nil), service.submitted...)
+}
+
+func newTestScrobbler(t *testing.T, path string, service Service) *Scrobbler {
+	t.Helper()
+	queue, err := OpenQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &Scrobbler{
+		services:      []Service{service},
+		queue:         queue,
+		RetryInterval: 10 * time.Millisecond,
+		announce:      make(chan Scrobble, 1),
+		flush:         make(chan struct{}, 1),
+		ctx:           ctx,
+		stop:          stop,
+	}
+}
+
+func track(id string, duration, progress time.Duration, playing bool) *spotify.Track {
+	return &spotify.Track{
+		ID:        sm.ID(id),
+		Title:     id,
+		IsPlaying: playing,
+		Timestamp: &spotify.Timestamp{Progress: sm.Numeric(progress.Milliseconds()), Duration: sm.Numeric(duration.Milliseconds())},
+	}
+}
+
+func TestObserveThreshold(t *testing.T) {
+	episode := track("episode", time.Hour, 30*time.Minute, true)
+	episode.Type = spotify.EpisodeItem
+
+	tests := []struct {
+		name   string
+		polls  []*spotify.Track
+		queued int
+	}{
+		{"half of a short track", []*spotify.Track{
+			track("a", 3*time.Minute, 0, true),
+			track("a", 3*time.Minute, 89*time.Second, true),
+		}, 0},
+		{"half of a short track reached", []*spotify.Track{
+			track("a", 3*time.Minute, 0, true),
+			track("a", 3*time.Minute, 90*time.Second, true),
+			track("a", 3*time.Minute, 150*time.Second, true),
+		}, 1},
+		{"4 minutes of a long track", []*spotify.Track{
+			track("a", 10*time.Minute, 0, true),
+			track("a", 10*time.Minute, 4*time.Minute, true),
+		}, 1},
+		{"paused past the threshold", []*spotify.Track{
+			track("a", 3*time.Minute, 2*time.Minute, false),
+		}, 1},
+		{"too short", []*spotify.Track{
+			track("a", 30*time.Second, 25*time.Second, true),
+		}, 0},
+		{"episode", []*spotify.Track{episode}, 0},
+		{"seeking back a little", []*spotify.Track{
+			track("a", 3*time.Minute, 100*time.Second, true),
+			track("a", 3*time.Minute, 96*time.Second, true),
+			track("a", 3*time.Minute, 120*time.Second, true),
+		}, 1},
+		{"replayed", []*spotify.Track{
+			track("a", 3*time.Minute, 100*time.Second, true),
+			track("a", 3*time.Minute, 0, true),
+			track("a", 3*time.Minute, 100*time.Second, true),
+		}, 2},
+		{"another track", []*spotify.Track{
+			track("a", 3*time.Minute, 100*time.Second, true),
+			track("b", 3*time.Minute, 100*time.Second, true),
+		}, 2},
+		{"nothing playing", []*spotify.Track{nil, {}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScrobbler(t, filepath.Join(t.TempDir(), "scrobbles.db"), &fakeService{})
+			defer s.Close()
+			for _, polled := range tt.polls {
+				s.Observe(polled)
+			}
+
+			if n, err := s.queue.Len("fake"); err != nil || n != tt.queued {
+				t.Fatalf("queued %d scrobbles (%v), want %d", n, err, tt.queued)
+			}
+		})
+	}
+}
+
+// waitSubmitted waits until the service got the scrobbles
+func waitSubmitted(t *testing.T, service *fakeService, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(service.Submitted()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("submitted %v, want %d scrobbles", service.Submitted(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return service.Submitted()
+}
+
+func TestRetry(t *testing.T) {
+	service := &fakeService{errs: []error{
+		&Error{Service: "fake", Message: "offline", Temporary: true},
+		context.DeadlineExceeded,
+	}}
+	s := newTestScrobbler(t, filepath.Join(t.TempDir(), "scrobbles.db"), service)
+	defer s.Close()
+	s.Observe(track("first", 3*time.Minute, 2*time.Minute, true))
+	s.Observe(track("second", 3*time.Minute, 2*time.Minute, true))
+
+	s.Start()
+	// both are submitted once the service recovers, in order
+	if submitted := waitSubmitted(t, service, 2); submitted[0] != "first" || submitted[1] != "second" {
+		t.Fatalf("submitted %v", submitted)
+	}
+}
+
+func TestPermanentErrorDrops(t *testing.T) {
+	service := &fakeService{errs: []error{&Error{Service: "fake", Message: "invalid", Temporary: false}}}
+	s := newTestScrobbler(t, filepath.Join(t.TempDir(), "scrobbles.db"), service)
+	defer s.Close()
+	s.Observe(track("dropped", 3*time.Minute, 2*time.Minute, true))
+	s.Observe(track("next", 3*time.Minute, 2*time.Minute, true))
+
+	s.Start()
+	if submitted := waitSubmitted(t, service, 1); len(submitted) != 1 || submitted[0] != "next" {
+		t.Fatalf("submitted %v, want the scrobble after the dropped one", submitted)
+	}
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scrobbles.db")
+	offline := &fakeService{down: true}
+	s := newTestScrobbler(t, path, offline)
+	s.Observe(track("pending", 3*time.Minute, 2*time.Minute, true))
+	s.Start()
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	online := &fakeService{}
+	s = newTestScrobbler(t, path, online)
+	defer s.Close()
+	s.Start()
+	if submitted := waitSubmitted(t, online, 1); submitted[0] != "pending" {
+		t.Fatalf("submitted %v after the restart", submitted)
+	}
+}
@@ -55,6 +55,8 @@ func (socket *Client) Send(event *Message) {
 				event.retries += 1
 				socket.Send(event)
 			}()
+		} else {
+			messagesDropped.WithLabelValues(event.label()).Inc()
 		}
 		return
 	}
@@ -64,8 +66,11 @@ func (socket *Client) Send(event *Message) {
 	socket.mu.RUnlock()
 
 	if err != nil {
+		messagesDropped.WithLabelValues(event.label()).Inc()
 		socket.Close(websocket.CloseInternalServerErr, err.Error())
+		return
 	}
+	messagesSent.WithLabelValues(event.label()).Inc()
 }
 
 func (socket *Client) Run() {
@@ -0,0 +1,64 @@
+package socket
+
+import (
+	"strconv"
+
+	"spotify/services/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	connections = metrics.Factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socket",
+		Name:      "connections",
+		Help:      "Open websocket connections.",
+	})
+	messagesSent = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socket",
+		Name:      "messages_sent_total",
+		Help:      "Messages sent to the websocket clients, by event.",
+	}, []string{"event"})
+	messagesDropped = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socket",
+		Name:      "messages_dropped_total",
+		Help:      "Messages that couldn't be sent to a websocket client, by event.",
+	}, []string{"event"})
+	heartbeatTimeouts = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socket",
+		Name:      "heartbeat_timeouts_total",
+		Help:      "Websocket clients disconnected for missing their heartbeat, by close code.",
+	}, []string{"code"})
+	broadcastDuration = metrics.Factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socket",
+		Name:      "broadcast_duration_seconds",
+		Help:      "Time to send a broadcast to every websocket client.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	})
+)
+
+// opNames label the messages without event
+var opNames = map[int]string{
+	SocketDispatch:     "DISPATCH",
+	SocketHello:        "HELLO",
+	SocketInitialize:   "INITIALIZE",
+	SocketHeartbeat:    "HEARTBEAT",
+	SocketHeartbeatACK: "HEARTBEAT_ACK",
+	SocketError:        "ERROR",
+}
+
+// label is the event of the message, or its opcode for the ones without
+func (sm *Message) label() string {
+	if sm.T != "" {
+		return sm.T
+	}
+	if name, ok := opNames[sm.OP]; ok {
+		return name
+	}
+	return strconv.Itoa(sm.OP)
+}
@@ -1,6 +1,7 @@
 package socket
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -18,6 +19,9 @@ type Socket[T any] struct {
 	mu    sync.RWMutex
 	state *T
 	pool  *Pool[string, *Client]
+
+	// called whenever the amount of initialized clients changes
+	onListeners func(int)
 }
 
 func New[T any]() *Socket[T] {
@@ -28,6 +32,8 @@ func New[T any]() *Socket[T] {
 
 func (s *Socket[T]) Handle(conn *websocket.Conn) {
 	client := NewClient(conn)
+	connections.Inc()
+	defer connections.Dec()
 	defer s.Unregister(client.ID)
 
 	s.Register(client)
@@ -60,16 +66,41 @@ func (s *Socket[T]) Listeners() int {
 	return s.pool.Len()
 }
 
+// OnListeners registers a callback fired with the new amount of listeners
+func (s *Socket[T]) OnListeners(fn func(int)) {
+	s.mu.Lock()
+	s.onListeners = fn
+	s.mu.Unlock()
+}
+
+func (s *Socket[T]) notifyListeners() {
+	s.mu.RLock()
+	fn := s.onListeners
+	s.mu.RUnlock()
+	if fn != nil {
+		fn(s.pool.Len())
+	}
+}
+
 func (s *Socket[T]) Broadcast(msg *Message) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	start := time.Now()
+	var wg sync.WaitGroup
 	for _, client := range s.pool.All() {
+		wg.Add(1)
 		go func(client *Client) { // send to each client in parallel
+			defer wg.Done()
 			if client != nil && client.isConnectionAlive {
 				client.Send(msg)
 			}
 		}(client)
 	}
+	// the fan-out is timed without holding the caller
+	go func() {
+		wg.Wait()
+		broadcastDuration.Observe(time.Since(start).Seconds())
+	}()
 }
 
 func (s *Socket[T]) Register(client *Client) {
@@ -85,6 +116,7 @@ func (s *Socket[T]) Register(client *Client) {
 func (s *Socket[T]) Unregister(clientID string) {
 	if s.pool.Has(clientID) {
 		s.pool.Delete(clientID)
+		s.notifyListeners()
 	}
 }
 
@@ -122,6 +154,7 @@ func (s *Socket[T]) WatchClient(client *Client) {
 				if !s.pool.Has(client.ID) {
 					go client.Send(Dispatch("INITIAL_STATE", &s.state))
 					s.pool.Set(client.ID, client)
+					s.notifyListeners()
 					continue
 				} else {
 					client.Close(CloseAlreadyAuthenticated, "Already authenticated") // force disconnect
@@ -155,6 +188,7 @@ func (s *Socket[T]) WatchClient(client *Client) {
 				}
 			}
 			// inactive/zombie connection
+			heartbeatTimeouts.WithLabelValues(strconv.Itoa(CloseByServerRequest)).Inc()
 			client.Close(CloseByServerRequest, "Disconnect by server request")
 			return
 		}
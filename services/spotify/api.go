@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"slices"
 	"time"
 
 	proto "spotify/protocols"
@@ -10,57 +11,112 @@ import (
 
 // Aliases for Spotify types
 type (
-	PlayerState        = sm.PlayerState
-	CurrentlyPlaying   = sm.CurrentlyPlaying
-	RecentlyPlayedItem = sm.RecentlyPlayedItem
+	PlayerState           = sm.PlayerState
+	CurrentlyPlaying      = sm.CurrentlyPlaying
+	RecentlyPlayedItem    = sm.RecentlyPlayedItem
+	RecentlyPlayedOptions = sm.RecentlyPlayedOptions
+)
+
+// Types of item that can be playing
+const (
+	TrackItem   = "track"
+	EpisodeItem = "episode"
+	AdItem      = "ad"
+	UnknownItem = "unknown"
 )
 
 /*-------------- SOCKET API ------------*/
 
 // Track represents a Spotify track
 type Track struct {
+	// Timestamp when the track was saved, only set for saved tracks
+	AddedAt *time.Time `json:"added_at,omitempty"`
 	// Album information
 	Album *Album `json:"album"`
 	// Artists involved in the track
 	Artists []Artist `json:"artists"`
+	// Disc number of the track in the album
+	DiscNumber sm.Numeric `json:"disc_number"`
+	// Duration of the track in milliseconds
+	Duration sm.Numeric `json:"duration"`
+	// Whether the track has explicit content
+	Explicit bool `json:"explicit"`
 	// Spotify ID of the track
 	ID sm.ID `json:"id"`
+	// International Standard Recording Code of the track
+	ISRC string `json:"isrc,omitempty"`
+	// Whether the track is a local file
+	IsLocal bool `json:"is_local"`
 	// Whether the track is currently playing
 	IsPlaying bool `json:"is_playing"`
 	// Timestamp when the track was played
 	PlayedAt *time.Time `json:"played_at,omitempty"`
+	// Popularity of the track, between 0 and 100
+	Popularity sm.Numeric `json:"popularity"`
+	// URL of a 30 seconds preview of the track
+	PreviewURL string `json:"preview_url,omitempty"`
+	// Show of the episode, only set for episodes
+	Show *Show `json:"show,omitempty"`
+	// Whether the gateway lost the processor since the track was received
+	Stale bool `json:"stale,omitempty"`
 	// timestamp information
 	Timestamp *Timestamp `json:"timestamp,omitempty"`
 	// Track title
 	Title string `json:"title"`
+	// Number of the track in its disc
+	TrackNumber sm.Numeric `json:"track_number"`
+	// Type of the item ("track", "episode", "ad" or "unknown")
+	Type string `json:"type"`
 	// URL of the track
 	URL string `json:"url"`
 }
 
 func (track *Track) ToProto() *proto.Track {
+	if track == nil {
+		return nil
+	}
 	var playedAt *int64 = nil
 	if track.PlayedAt != nil {
 		played := track.PlayedAt.UnixMilli()
 		playedAt = &played
 	}
+	var addedAt *int64 = nil
+	if track.AddedAt != nil {
+		added := track.AddedAt.UnixMilli()
+		addedAt = &added
+	}
 	artists := make([]*proto.Artist, len(track.Artists))
 	for i, artist := range track.Artists {
 		artists[i] = artist.ToProto()
 	}
 
 	return &proto.Track{
-		Album:     track.Album.ToProto(),
-		Artist:    artists,
-		ID:        track.ID.String(),
-		IsPlaying: track.IsPlaying,
-		PlayedAt:  playedAt,
-		Timestamp: track.Timestamp.ToProto(),
-		Title:     track.Title,
-		URL:       track.URL,
+		AddedAt:     addedAt,
+		Album:       track.Album.ToProto(),
+		Artist:      artists,
+		DiscNumber:  int64(track.DiscNumber),
+		Duration:    int64(track.Duration),
+		Explicit:    track.Explicit,
+		ID:          track.ID.String(),
+		ISRC:        track.ISRC,
+		IsLocal:     track.IsLocal,
+		IsPlaying:   track.IsPlaying,
+		PlayedAt:    playedAt,
+		Popularity:  int64(track.Popularity),
+		PreviewURL:  track.PreviewURL,
+		Show:        track.Show.ToProto(),
+		Timestamp:   track.Timestamp.ToProto(),
+		Title:       track.Title,
+		TrackNumber: int64(track.TrackNumber),
+		Type:        track.Type,
+		URL:         track.URL,
 	}
 }
 
 func FromProtoToTrack(pb *proto.Track) *Track {
+	if pb == nil {
+		return nil
+	}
 	artists := make([]Artist, len(pb.Artist))
 	playedAt := &time.Time{}
 	if pb.PlayedAt != nil {
@@ -69,23 +125,34 @@ func FromProtoToTrack(pb *proto.Track) *Track {
 	} else {
 		playedAt = nil
 	}
+	var addedAt *time.Time = nil
+	if pb.AddedAt != nil {
+		added := time.UnixMilli(*pb.AddedAt)
+		addedAt = &added
+	}
 	for i, artist := range pb.Artist {
-		artists[i] = Artist{Name: artist.Name, URL: artist.URL}
+		artists[i] = FromProtoToArtist(artist)
 	}
 	return &Track{
-		Album: &Album{
-			ID:       sm.ID(pb.Album.ID),
-			Name:     pb.Album.Name,
-			URL:      pb.Album.URL,
-			ImageURL: pb.Album.ImageURL,
-		},
-		Artists:   artists,
-		ID:        sm.ID(pb.ID),
-		IsPlaying: pb.IsPlaying,
-		PlayedAt:  playedAt,
-		Timestamp: FromProtoToTimestamp(pb.Timestamp),
-		Title:     pb.Title,
-		URL:       pb.URL,
+		AddedAt:     addedAt,
+		Album:       FromProtoToAlbum(pb.Album),
+		Artists:     artists,
+		DiscNumber:  sm.Numeric(pb.DiscNumber),
+		Duration:    sm.Numeric(pb.Duration),
+		Explicit:    pb.Explicit,
+		ID:          sm.ID(pb.ID),
+		ISRC:        pb.ISRC,
+		IsLocal:     pb.IsLocal,
+		IsPlaying:   pb.IsPlaying,
+		PlayedAt:    playedAt,
+		Popularity:  sm.Numeric(pb.Popularity),
+		PreviewURL:  pb.PreviewURL,
+		Show:        FromProtoToShow(pb.Show),
+		Timestamp:   FromProtoToTimestamp(pb.Timestamp),
+		Title:       pb.Title,
+		TrackNumber: sm.Numeric(pb.TrackNumber),
+		Type:        pb.Type,
+		URL:         pb.URL,
 	}
 }
 
@@ -119,16 +186,43 @@ func FromProtoToTimestamp(pb *proto.Timestamp) *Timestamp {
 
 // Artist represents an artist in volved in a track
 type Artist struct {
+	// Number of followers, only set once enriched
+	Followers sm.Numeric `json:"followers,omitempty"`
+	// Genres of the artist, only set once enriched
+	Genres []string `json:"genres,omitempty"`
+	// Spotify ID of the artist
+	ID sm.ID `json:"id,omitempty"`
+	// URL of the artist image, only set once enriched
+	ImageURL string `json:"image_url,omitempty"`
 	// Artist name
 	Name string `json:"name"`
+	// Popularity of the artist between 0 and 100, only set once enriched
+	Popularity sm.Numeric `json:"popularity,omitempty"`
 	// URL of the artist
 	URL string `json:"url"`
 }
 
 func (artist *Artist) ToProto() *proto.Artist {
 	return &proto.Artist{
-		Name: artist.Name,
-		URL:  artist.URL,
+		Followers:  int64(artist.Followers),
+		Genres:     artist.Genres,
+		ID:         artist.ID.String(),
+		ImageURL:   artist.ImageURL,
+		Name:       artist.Name,
+		Popularity: int64(artist.Popularity),
+		URL:        artist.URL,
+	}
+}
+
+func FromProtoToArtist(pb *proto.Artist) Artist {
+	return Artist{
+		Followers:  sm.Numeric(pb.Followers),
+		Genres:     pb.Genres,
+		ID:         sm.ID(pb.ID),
+		ImageURL:   pb.ImageURL,
+		Name:       pb.Name,
+		Popularity: sm.Numeric(pb.Popularity),
+		URL:        pb.URL,
 	}
 }
 
@@ -140,15 +234,320 @@ type Album struct {
 	Name string `json:"name"`
 	// Spotify ID of the album
 	ID sm.ID `json:"id"`
+	// Release date of the album, its precision may be year, month or day
+	ReleaseDate string `json:"release_date,omitempty"`
 	// URL of the album
 	URL string `json:"url"`
 }
 
 func (album *Album) ToProto() *proto.Album {
+	if album == nil {
+		return nil
+	}
 	return &proto.Album{
-		ImageURL: album.ImageURL,
-		Name:     album.Name,
-		ID:       album.ID.String(),
-		URL:      album.URL,
+		ImageURL:    album.ImageURL,
+		Name:        album.Name,
+		ID:          album.ID.String(),
+		ReleaseDate: album.ReleaseDate,
+		URL:         album.URL,
+	}
+}
+
+func FromProtoToAlbum(pb *proto.Album) *Album {
+	if pb == nil {
+		return nil
+	}
+	return &Album{
+		ID:          sm.ID(pb.ID),
+		Name:        pb.Name,
+		URL:         pb.URL,
+		ImageURL:    pb.ImageURL,
+		ReleaseDate: pb.ReleaseDate,
+	}
+}
+
+// Show represents the podcast show of an episode
+type Show struct {
+	// URL of the show cover art
+	ImageURL string `json:"image_url"`
+	// Name of the show
+	Name string `json:"name"`
+	// Spotify ID of the show
+	ID sm.ID `json:"id"`
+	// Publisher of the show
+	Publisher string `json:"publisher"`
+	// URL of the show
+	URL string `json:"url"`
+}
+
+func (show *Show) ToProto() *proto.Show {
+	if show == nil {
+		return nil
+	}
+	return &proto.Show{
+		ImageURL:  show.ImageURL,
+		Name:      show.Name,
+		ID:        show.ID.String(),
+		Publisher: show.Publisher,
+		URL:       show.URL,
+	}
+}
+
+func FromProtoToShow(pb *proto.Show) *Show {
+	if pb == nil {
+		return nil
+	}
+	return &Show{
+		ID:        sm.ID(pb.ID),
+		Name:      pb.Name,
+		Publisher: pb.Publisher,
+		URL:       pb.URL,
+		ImageURL:  pb.ImageURL,
+	}
+}
+
+// Queue represents the item playing and the ones up next
+type Queue struct {
+	// Item playing, if any
+	Current *Track `json:"current,omitempty"`
+	// Items up next, the next one first
+	Items []*Track `json:"items"`
+}
+
+// SameItems reports whether both queues have the same items up next
+func (queue *Queue) SameItems(other *Queue) bool {
+	if queue == nil || other == nil {
+		return queue == other
+	}
+	return slices.EqualFunc(queue.Items, other.Items, func(a, b *Track) bool {
+		return a.ID == b.ID
+	})
+}
+
+func (queue *Queue) ToProto() *proto.Queue {
+	if queue == nil {
+		return nil
+	}
+	items := make([]*proto.Track, len(queue.Items))
+	for i, item := range queue.Items {
+		items[i] = item.ToProto()
+	}
+	return &proto.Queue{
+		Current: queue.Current.ToProto(),
+		Items:   items,
+	}
+}
+
+func FromProtoToQueue(pb *proto.Queue) *Queue {
+	if pb == nil {
+		return nil
+	}
+	items := make([]*Track, len(pb.Items))
+	for i, item := range pb.Items {
+		items[i] = FromProtoToTrack(item)
+	}
+	return &Queue{
+		Current: FromProtoToTrack(pb.Current),
+		Items:   items,
+	}
+}
+
+// Playlist represents a playlist of the user
+type Playlist struct {
+	// Whether other users can modify the playlist
+	Collaborative bool `json:"collaborative"`
+	// Description of the playlist
+	Description string `json:"description,omitempty"`
+	// Spotify ID of the playlist
+	ID sm.ID `json:"id"`
+	// URL of the playlist cover art
+	ImageURL string `json:"image_url"`
+	// Name of the playlist
+	Name string `json:"name"`
+	// Display name of the owner
+	Owner string `json:"owner"`
+	// Whether the playlist is public
+	Public bool `json:"public"`
+	// Number of tracks in the playlist
+	Tracks sm.Numeric `json:"tracks"`
+	// URL of the playlist
+	URL string `json:"url"`
+}
+
+func (playlist *Playlist) ToProto() *proto.Playlist {
+	if playlist == nil {
+		return nil
+	}
+	return &proto.Playlist{
+		Collaborative: playlist.Collaborative,
+		Description:   playlist.Description,
+		ID:            playlist.ID.String(),
+		ImageURL:      playlist.ImageURL,
+		Name:          playlist.Name,
+		Owner:         playlist.Owner,
+		Public:        playlist.Public,
+		Tracks:        int64(playlist.Tracks),
+		URL:           playlist.URL,
+	}
+}
+
+func FromProtoToPlaylist(pb *proto.Playlist) *Playlist {
+	if pb == nil {
+		return nil
+	}
+	return &Playlist{
+		Collaborative: pb.Collaborative,
+		Description:   pb.Description,
+		ID:            sm.ID(pb.ID),
+		ImageURL:      pb.ImageURL,
+		Name:          pb.Name,
+		Owner:         pb.Owner,
+		Public:        pb.Public,
+		Tracks:        sm.Numeric(pb.Tracks),
+		URL:           pb.URL,
+	}
+}
+
+// State is the item playing along with the player playing it
+type State struct {
+	// Item playing, or played last while nothing plays
+	Track *Track `json:"track"`
+	// Player state, only set while something is playing
+	Player *Player `json:"player,omitempty"`
+}
+
+func (state *State) ToProto() *proto.State {
+	if state == nil {
+		return nil
+	}
+	return &proto.State{
+		Track:  state.Track.ToProto(),
+		Player: state.Player.ToProto(),
+	}
+}
+
+func FromProtoToState(pb *proto.State) *State {
+	if pb == nil {
+		return nil
+	}
+	return &State{
+		Track:  FromProtoToTrack(pb.Track),
+		Player: FromProtoToPlayer(pb.Player),
+	}
+}
+
+// Player represents the state of the player
+type Player struct {
+	// Context the item is played from
+	Context *PlaybackContext `json:"context,omitempty"`
+	// Device playing the item
+	Device *Device `json:"device,omitempty"`
+	// Repeat state ("off", "track" or "context")
+	Repeat string `json:"repeat"`
+	// Whether shuffle is on
+	Shuffle bool `json:"shuffle"`
+}
+
+// SameDevice reports whether both players are on the same device
+func (player *Player) SameDevice(other *Player) bool {
+	if player == nil || other == nil || player.Device == nil || other.Device == nil {
+		return true
+	}
+	return player.Device.ID == other.Device.ID && player.Device.Name == other.Device.Name
+}
+
+func (player *Player) ToProto() *proto.Player {
+	if player == nil {
+		return nil
+	}
+	return &proto.Player{
+		Context: player.Context.ToProto(),
+		Device:  player.Device.ToProto(),
+		Repeat:  player.Repeat,
+		Shuffle: player.Shuffle,
+	}
+}
+
+func FromProtoToPlayer(pb *proto.Player) *Player {
+	if pb == nil {
+		return nil
+	}
+	return &Player{
+		Context: FromProtoToContext(pb.Context),
+		Device:  FromProtoToDevice(pb.Device),
+		Repeat:  pb.Repeat,
+		Shuffle: pb.Shuffle,
+	}
+}
+
+// Device represents a Spotify Connect device
+type Device struct {
+	// Spotify ID of the device
+	ID sm.ID `json:"id"`
+	// Name of the device
+	Name string `json:"name"`
+	// Type of the device, such as "Computer", "Smartphone" or "Speaker"
+	Type string `json:"type"`
+	// Volume in percent
+	Volume sm.Numeric `json:"volume"`
+}
+
+func (device *Device) ToProto() *proto.Device {
+	if device == nil {
+		return nil
+	}
+	return &proto.Device{
+		ID:     device.ID.String(),
+		Name:   device.Name,
+		Type:   device.Type,
+		Volume: int64(device.Volume),
+	}
+}
+
+func FromProtoToDevice(pb *proto.Device) *Device {
+	if pb == nil {
+		return nil
+	}
+	return &Device{
+		ID:     sm.ID(pb.ID),
+		Name:   pb.Name,
+		Type:   pb.Type,
+		Volume: sm.Numeric(pb.Volume),
+	}
+}
+
+// PlaybackContext represents where the item is played from
+type PlaybackContext struct {
+	// Name of the playlist, album, artist or show
+	Name string `json:"name"`
+	// Type of the context ("playlist", "album", "artist", "show"...)
+	Type string `json:"type"`
+	// Spotify URI of the context
+	URI sm.URI `json:"uri"`
+	// URL of the context
+	URL string `json:"url"`
+}
+
+func (context *PlaybackContext) ToProto() *proto.Context {
+	if context == nil {
+		return nil
+	}
+	return &proto.Context{
+		Name: context.Name,
+		Type: context.Type,
+		URI:  string(context.URI),
+		URL:  context.URL,
+	}
+}
+
+func FromProtoToContext(pb *proto.Context) *PlaybackContext {
+	if pb == nil {
+		return nil
+	}
+	return &PlaybackContext{
+		Name: pb.Name,
+		Type: pb.Type,
+		URI:  sm.URI(pb.URI),
+		URL:  pb.URL,
 	}
 }
@@ -0,0 +1,212 @@
+package spotify
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	sm "github.com/zmb3/spotify/v2"
+)
+
+const (
+	DefaultArtistCacheSize = 512
+	DefaultArtistCacheTTL  = 24 * time.Hour
+
+	// Spotify allows up to 50 artists per request
+	maxArtistsPerRequest = 50
+	// Wait before saving the cache, so the batches of an enrichment are saved
+	// once. Stopping meanwhile only loses the latest artists, fetched again.
+	artistsSaveDelay = 5 * time.Second
+)
+
+type cachedArtist struct {
+	Artist    Artist    `json:"artist"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// artistCache is a LRU of enriched artists with TTL, optionally persisted on disk
+type artistCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	path  string
+	items map[sm.ID]*list.Element
+	order *list.List
+	// pending save, nil when saved
+	saveTimer *time.Timer
+}
+
+func newArtistCache(size int, ttl time.Duration, path string) *artistCache {
+	cache := &artistCache{
+		size:  size,
+		ttl:   ttl,
+		path:  path,
+		items: make(map[sm.ID]*list.Element),
+		order: list.New(),
+	}
+	cache.load()
+	return cache
+}
+
+// Get returns the cached artist if it didn't expire yet
+func (cache *artistCache) Get(id sm.ID) (Artist, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	elem, ok := cache.items[id]
+	if !ok {
+		return Artist{}, false
+	}
+	entry := elem.Value.(*cachedArtist)
+	if time.Since(entry.FetchedAt) > cache.ttl {
+		cache.order.Remove(elem)
+		delete(cache.items, id)
+		return Artist{}, false
+	}
+	cache.order.MoveToFront(elem)
+	return entry.Artist, true
+}
+
+// Set caches the artists, the file is saved in the background
+func (cache *artistCache) Set(artists ...Artist) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, artist := range artists {
+		cache.set(&cachedArtist{Artist: artist, FetchedAt: time.Now()})
+	}
+	if cache.path != "" && cache.saveTimer == nil {
+		cache.saveTimer = time.AfterFunc(artistsSaveDelay, cache.save)
+	}
+}
+
+func (cache *artistCache) set(entry *cachedArtist) {
+	if elem, ok := cache.items[entry.Artist.ID]; ok {
+		elem.Value = entry
+		cache.order.MoveToFront(elem)
+		return
+	}
+	cache.items[entry.Artist.ID] = cache.order.PushFront(entry)
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.items, oldest.Value.(*cachedArtist).Artist.ID)
+	}
+}
+
+// Fill replaces the artists found in the cache, reporting the ones missing
+func (cache *artistCache) Fill(artists []Artist) []sm.ID {
+	var missing []sm.ID
+	for i, artist := range artists {
+		if artist.ID == "" {
+			continue
+		}
+		if cached, ok := cache.Get(artist.ID); ok {
+			artists[i] = cached
+		} else {
+			missing = append(missing, artist.ID)
+		}
+	}
+	return missing
+}
+
+func (cache *artistCache) load() {
+	if cache.path == "" {
+		return
+	}
+	data, err := os.ReadFile(cache.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("error while loading artists cache: %v", err)
+		}
+		return
+	}
+
+	var entries []*cachedArtist
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("error while loading artists cache: %v", err)
+		return
+	}
+	// entries are saved from the most to the least recently used
+	for i := len(entries) - 1; i >= 0; i-- {
+		if time.Since(entries[i].FetchedAt) <= cache.ttl {
+			cache.set(entries[i])
+		}
+	}
+}
+
+func (cache *artistCache) save() {
+	if cache.path == "" {
+		return
+	}
+	cache.mu.Lock()
+	cache.saveTimer = nil
+	entries := make([]*cachedArtist, 0, cache.order.Len())
+	for elem := cache.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(*cachedArtist))
+	}
+	cache.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err == nil {
+		// write aside and rename, so a crash never leaves a truncated cache
+		if err = os.WriteFile(cache.path+".tmp", data, 0o600); err == nil {
+			err = os.Rename(cache.path+".tmp", cache.path)
+		}
+	}
+	if err != nil {
+		log.Printf("error while saving artists cache: %v", err)
+	}
+}
+
+// EnrichArtists fetches the artists missing in the cache with batched requests.
+// The enriched copy of the artists is sent on the returned channel once done,
+// or with the artists known so far once ctx is done.
+func (c *SpotifyClient) EnrichArtists(ctx context.Context, artists []Artist) <-chan []Artist {
+	result := make(chan []Artist, 1)
+	enriched := make([]Artist, len(artists))
+	copy(enriched, artists)
+
+	missing := c.artists.Fill(enriched)
+	if len(missing) == 0 {
+		result <- enriched
+		return result
+	}
+
+	go func() {
+		for start := 0; start < len(missing); start += maxArtistsPerRequest {
+			end := min(start+maxArtistsPerRequest, len(missing))
+			fulls, err := c.Client.GetArtists(ctx, missing[start:end]...)
+			if err != nil {
+				log.Printf("error while enriching artists: %v", err)
+				break
+			}
+
+			fetched := make([]Artist, 0, len(fulls))
+			for _, full := range fulls {
+				if full != nil {
+					fetched = append(fetched, c.newArtist(full))
+				}
+			}
+			c.artists.Set(fetched...)
+		}
+		c.artists.Fill(enriched)
+		result <- enriched
+	}()
+	return result
+}
+
+func (c *SpotifyClient) newArtist(full *sm.FullArtist) Artist {
+	return Artist{
+		Followers:  full.Followers.Count,
+		Genres:     full.Genres,
+		ID:         full.ID,
+		ImageURL:   c.imageURL(full.Images),
+		Name:       full.Name,
+		Popularity: full.Popularity,
+		URL:        full.ExternalURLs["spotify"],
+	}
+}
@@ -0,0 +1,109 @@
+package spotify
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	sm "github.com/zmb3/spotify/v2"
+)
+
+const (
+	// Names of the played contexts kept, the least recently played go first
+	contextNamesSize = 256
+	// Wait for the name of a context, the poll goes on without it otherwise
+	contextNameTimeout = 2 * time.Second
+)
+
+type contextName struct {
+	uri  sm.URI
+	name string
+}
+
+// contextNameCache is a LRU of the names of the played contexts
+type contextNameCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[sm.URI]*list.Element
+	order *list.List
+}
+
+func newContextNameCache(size int) *contextNameCache {
+	return &contextNameCache{
+		size:  size,
+		items: make(map[sm.URI]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (cache *contextNameCache) Get(uri sm.URI) (string, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	elem, ok := cache.items[uri]
+	if !ok {
+		return "", false
+	}
+	cache.order.MoveToFront(elem)
+	return elem.Value.(*contextName).name, true
+}
+
+func (cache *contextNameCache) Set(uri sm.URI, name string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if elem, ok := cache.items[uri]; ok {
+		elem.Value.(*contextName).name = name
+		cache.order.MoveToFront(elem)
+		return
+	}
+	cache.items[uri] = cache.order.PushFront(&contextName{uri: uri, name: name})
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.items, oldest.Value.(*contextName).uri)
+	}
+}
+
+// contextName looks up (once) the name of the playlist, album, artist or show
+// being played. The lookup is short, the name is retried on the next poll.
+func (c *SpotifyClient) contextName(ctx context.Context, pc sm.PlaybackContext) string {
+	if name, ok := c.contextNames.Get(pc.URI); ok {
+		return name
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, contextNameTimeout)
+	defer cancel()
+
+	uri := strings.Split(string(pc.URI), ":")
+	id := sm.ID(uri[len(uri)-1])
+
+	var name string
+	switch pc.Type {
+	case "playlist":
+		if playlist, err := c.Client.GetPlaylist(ctx, id, sm.Fields("name")); err == nil {
+			name = playlist.Name
+		}
+	case "album":
+		if album, err := c.Client.GetAlbum(ctx, id); err == nil {
+			name = album.Name
+		}
+	case "artist":
+		if artist, err := c.Client.GetArtist(ctx, id); err == nil {
+			name = artist.Name
+		}
+	case "show":
+		if show, err := c.Client.GetShow(ctx, id); err == nil {
+			name = show.Name
+		}
+	default:
+		return ""
+	}
+
+	if name != "" {
+		c.contextNames.Set(pc.URI, name)
+	}
+	return name
+}
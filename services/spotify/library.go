@@ -0,0 +1,207 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sm "github.com/zmb3/spotify/v2"
+)
+
+const (
+	// Spotify returns up to 50 items per page of the library, 20 by default
+	MaxLibraryLimit     = 50
+	DefaultLibraryLimit = 20
+
+	DefaultLibraryCacheTTL = 5 * time.Minute
+)
+
+var ErrInvalidTimeRange = errors.New("spotify: time range must be short_term, medium_term or long_term")
+
+// LibraryOptions selects a page of the top items or the library
+type LibraryOptions struct {
+	// Time range of the top items ("short_term", "medium_term" or "long_term")
+	TimeRange string
+	// Max items to return
+	Limit int
+	// Index of the first item
+	Offset int
+}
+
+// Page is a page of the top items or the library
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// Total of items available
+	Total sm.Numeric `json:"total"`
+}
+
+type cachedPage struct {
+	page      any
+	expiresAt time.Time
+}
+
+// libraryCache keeps the pages requested recently, the top items and the
+// library change slowly and every listener of a page would request it
+type libraryCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	pages map[string]*cachedPage
+}
+
+func newLibraryCache(ttl time.Duration) *libraryCache {
+	return &libraryCache{ttl: ttl, pages: make(map[string]*cachedPage)}
+}
+
+func (cache *libraryCache) Get(key string) (any, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.pages[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.page, true
+}
+
+func (cache *libraryCache) Set(key string, page any) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	// offsets are arbitrary, drop the expired pages so the cache doesn't grow forever
+	for k, entry := range cache.pages {
+		if now.After(entry.expiresAt) {
+			delete(cache.pages, k)
+		}
+	}
+	cache.pages[key] = &cachedPage{page: page, expiresAt: now.Add(cache.ttl)}
+}
+
+// cached returns the page of the key if cached, fetching it otherwise
+func cached[T any](c *SpotifyClient, key string, fetch func() (*Page[T], error)) (*Page[T], error) {
+	if page, ok := c.library.Get(key); ok {
+		return page.(*Page[T]), nil
+	}
+	page, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.library.Set(key, page)
+	return page, nil
+}
+
+// options validates the options, returning the request options and the cache key
+func (opts LibraryOptions) options(path string, ranged bool) ([]sm.RequestOption, string, error) {
+	if opts.Limit < 1 || opts.Limit > MaxLibraryLimit {
+		opts.Limit = DefaultLibraryLimit
+	}
+	opts.Offset = max(opts.Offset, 0)
+
+	options := []sm.RequestOption{sm.Limit(opts.Limit), sm.Offset(opts.Offset)}
+	if ranged {
+		switch sm.Range(opts.TimeRange) {
+		case "":
+			opts.TimeRange = string(sm.MediumTermRange)
+		case sm.ShortTermRange, sm.MediumTermRange, sm.LongTermRange:
+		default:
+			return nil, "", ErrInvalidTimeRange
+		}
+		options = append(options, sm.Timerange(sm.Range(opts.TimeRange)))
+	} else {
+		opts.TimeRange = ""
+	}
+	return options, fmt.Sprintf("%s?%s&%d&%d", path, opts.TimeRange, opts.Limit, opts.Offset), nil
+}
+
+// GetTopArtists returns a page of the artists the user listens to the most
+func (c *SpotifyClient) GetTopArtists(ctx context.Context, opts LibraryOptions) (*Page[Artist], error) {
+	options, key, err := opts.options("top/artists", true)
+	if err != nil {
+		return nil, err
+	}
+	return cached(c, key, func() (*Page[Artist], error) {
+		top, err := c.Client.CurrentUsersTopArtists(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		artists := make([]Artist, len(top.Artists))
+		for i := range top.Artists {
+			artists[i] = c.newArtist(&top.Artists[i])
+		}
+		// top artists come enriched, spare the lookups on track change
+		c.artists.Set(artists...)
+		return &Page[Artist]{Items: artists, Total: top.Total}, nil
+	})
+}
+
+// GetTopTracks returns a page of the tracks the user listens to the most
+func (c *SpotifyClient) GetTopTracks(ctx context.Context, opts LibraryOptions) (*Page[*Track], error) {
+	options, key, err := opts.options("top/tracks", true)
+	if err != nil {
+		return nil, err
+	}
+	return cached(c, key, func() (*Page[*Track], error) {
+		top, err := c.Client.CurrentUsersTopTracks(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		tracks := make([]*Track, len(top.Tracks))
+		for i := range top.Tracks {
+			tracks[i] = c.newTrack(&top.Tracks[i], false)
+		}
+		return &Page[*Track]{Items: tracks, Total: top.Total}, nil
+	})
+}
+
+// GetSavedTracks returns a page of the tracks saved by the user, the latest first
+func (c *SpotifyClient) GetSavedTracks(ctx context.Context, opts LibraryOptions) (*Page[*Track], error) {
+	options, key, err := opts.options("tracks", false)
+	if err != nil {
+		return nil, err
+	}
+	return cached(c, key, func() (*Page[*Track], error) {
+		saved, err := c.Client.CurrentUsersTracks(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		tracks := make([]*Track, len(saved.Tracks))
+		for i := range saved.Tracks {
+			tracks[i] = c.newTrack(&saved.Tracks[i].FullTrack, false)
+			if addedAt, err := time.Parse(sm.TimestampLayout, saved.Tracks[i].AddedAt); err == nil {
+				tracks[i].AddedAt = &addedAt
+			}
+		}
+		return &Page[*Track]{Items: tracks, Total: saved.Total}, nil
+	})
+}
+
+// GetPlaylists returns a page of the playlists owned or followed by the user
+func (c *SpotifyClient) GetPlaylists(ctx context.Context, opts LibraryOptions) (*Page[*Playlist], error) {
+	options, key, err := opts.options("playlists", false)
+	if err != nil {
+		return nil, err
+	}
+	return cached(c, key, func() (*Page[*Playlist], error) {
+		page, err := c.Client.CurrentUsersPlaylists(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		playlists := make([]*Playlist, len(page.Playlists))
+		for i, playlist := range page.Playlists {
+			playlists[i] = &Playlist{
+				Collaborative: playlist.Collaborative,
+				Description:   playlist.Description,
+				ID:            playlist.ID,
+				ImageURL:      c.imageURL(playlist.Images),
+				Name:          playlist.Name,
+				Owner:         playlist.Owner.DisplayName,
+				Public:        playlist.IsPublic,
+				Tracks:        playlist.Tracks.Total,
+				URL:           playlist.ExternalURLs["spotify"],
+			}
+		}
+		return &Page[*Playlist]{Items: playlists, Total: page.Total}, nil
+	})
+}
@@ -0,0 +1,63 @@
+package spotify
+
+import (
+	"sync"
+	"time"
+)
+
+// Listeners sums the websocket listeners of every process sharing the bus
+type Listeners struct {
+	mu     sync.Mutex
+	counts map[string]listenersCount
+
+	// signaled whenever a count changes
+	changed chan struct{}
+}
+
+type listenersCount struct {
+	n    int64
+	seen time.Time
+}
+
+func newListeners() *Listeners {
+	return &Listeners{
+		counts:  make(map[string]listenersCount),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Set stores the listeners announced by a process
+func (l *Listeners) Set(id string, n int64) {
+	l.mu.Lock()
+	old := l.counts[id]
+	if n > 0 {
+		l.counts[id] = listenersCount{n: n, seen: time.Now()}
+	} else {
+		delete(l.counts, id)
+	}
+	l.mu.Unlock()
+
+	if old.n != n {
+		select {
+		case l.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Total returns the listeners of the cluster, processes which stopped
+// announcing theirs are left out
+func (l *Listeners) Total() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var total int64
+	for id, count := range l.counts {
+		if time.Since(count.seen) > 3*listenersHeartbeat {
+			delete(l.counts, id)
+			continue
+		}
+		total += count.n
+	}
+	return total
+}
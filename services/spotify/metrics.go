@@ -0,0 +1,32 @@
+package spotify
+
+import (
+	"net/http"
+	"strconv"
+
+	"spotify/services/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var apiRequests = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "api",
+	Name:      "requests_total",
+	Help:      "Requests to the Spotify Web API, by status code or \"error\" without answer.",
+}, []string{"status"})
+
+// countedTransport counts the requests to Spotify by status
+type countedTransport struct {
+	next http.RoundTripper
+}
+
+func (t countedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		apiRequests.WithLabelValues("error").Inc()
+		return res, err
+	}
+	apiRequests.WithLabelValues(strconv.Itoa(res.StatusCode)).Inc()
+	return res, nil
+}
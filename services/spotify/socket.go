@@ -3,71 +3,339 @@ package spotify
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"slices"
+	"time"
 
 	"spotify/protocols"
+	"spotify/services/bus"
+	"spotify/services/config"
 	"spotify/services/socket"
+	"spotify/services/tracing"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
-	"github.com/knadh/koanf/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 )
 
-func Socket(client *SpotifyClient, k *koanf.Koanf, grpc protocols.SpotifyClient) fiber.Handler {
-	client.Socket = socket.New[Track]()
-	// start poll data
-	track, err := grpc.GetTrack(context.Background(), &protocols.Request{ID: fmt.Sprintf("%d", os.Getpid())})
+const (
+	// Topics of the bus
+	TopicEvents    = "events"
+	TopicListeners = "listeners"
+
+	// Leadership of the process reading the processor stream
+	consumerLeader = "consumer"
+	// Cadence the listeners are announced at, they expire after missing 3
+	listenersHeartbeat = 15 * time.Second
+	// Wait before reconnecting to the processor, doubled on each failure
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+	// Wait for the state on start, the gateway starts without it afterwards
+	initialStateTimeout = 10 * time.Second
+)
+
+// Socket feeds the websocket clients from the bus. A single process of the
+// cluster (the leader) reads the processor stream and publishes it.
+func Socket(client *SpotifyClient, w *config.Watcher, grpc protocols.SpotifyClient, bus bus.EventBus) (fiber.Handler, error) {
+	client.Socket = socket.New[State]()
+	client.Listeners = newListeners()
+	// start poll data, without the processor the state comes once connected
+	ctx, cancel := context.WithTimeout(context.Background(), initialStateTimeout)
+	state, err := grpc.GetTrack(ctx, &protocols.Request{ID: gatewayID()})
+	cancel()
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("Processor unavailable, starting without state: %v", err)
+	} else {
+		client.Socket.SetState(FromProtoToState(state))
+	}
+	client.upstream = newUpstream(err == nil)
+
+	if _, err := bus.Subscribe(TopicEvents, func(data []byte) {
+		res := &protocols.Reponse{}
+		if err := proto.Unmarshal(data, res); err != nil {
+			log.Printf("error while reading event: %v", err)
+			return
+		}
+		handle(client, res)
+	}); err != nil {
+		return nil, fmt.Errorf("subscribing to %s: %w", TopicEvents, err)
 	}
-	client.Socket.SetState(FromProtoToTrack(track))
+	if _, err := bus.Subscribe(TopicListeners, func(data []byte) {
+		demand := &protocols.Demand{}
+		if err := proto.Unmarshal(data, demand); err != nil {
+			log.Printf("error while reading listeners: %v", err)
+			return
+		}
+		client.Listeners.Set(demand.GetID(), demand.GetListeners())
+	}); err != nil {
+		return nil, fmt.Errorf("subscribing to %s: %w", TopicListeners, err)
+	}
+
+	// keep only the latest amount of listeners pending to be announced
+	listeners := make(chan int, 1)
+	client.Socket.OnListeners(func(n int) {
+		select {
+		case <-listeners:
+		default:
+		}
+		listeners <- n
+	})
 
-	go poll(client, grpc)
-	return websocket.New(client.Socket.Handle, websocket.Config{
-		Origins:         k.Strings("websocket.origins"),
+	go announce(bus, listeners)
+	go consume(client, grpc, bus)
+	k := w.Koanf()
+	upgrade := websocket.New(client.Socket.Handle, websocket.Config{
+		// checked below instead, the origins can be reloaded
+		Origins:         []string{"*"},
 		ReadBufferSize:  k.Int("websocket.read_buffer_size"),
 		WriteBufferSize: k.Int("websocket.write_buffer_size"),
 	})
+	return func(c *fiber.Ctx) error {
+		if !allowedOrigin(w.Koanf().Strings("websocket.origins"), c.Get(fiber.HeaderOrigin)) {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return upgrade(c)
+	}, nil
+}
+
+// allowedOrigin reports whether origin is one of origins, every origin is
+// allowed without origins or with "*" first
+func allowedOrigin(origins []string, origin string) bool {
+	if len(origins) == 0 || origins[0] == "*" {
+		return true
+	}
+	return slices.Contains(origins, origin)
+}
+
+// consume reads the processor stream whenever this process is elected,
+// publishing every event to the bus
+func consume(client *SpotifyClient, grpc protocols.SpotifyClient, bus bus.EventBus) {
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		lost, err := bus.Elect(ctx, consumerLeader)
+		if err != nil {
+			cancel()
+			continue
+		}
+		go func() {
+			<-lost
+			cancel()
+		}()
+
+		lead(ctx, client, grpc, bus)
+		cancel()
+		log.Println("Lost the leadership of the processor stream")
+	}
+}
+
+// lead keeps the processor stream open until ctx is done, reconnecting with
+// exponential backoff while the processor is down
+func lead(ctx context.Context, client *SpotifyClient, grpc protocols.SpotifyClient, bus bus.EventBus) {
+	backoff := minReconnectDelay
+	for {
+		connected, err := poll(ctx, client, grpc, bus)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = minReconnectDelay
+			log.Printf("error while reading stream, reconnecting in %s: %v", backoff, err)
+		} else {
+			log.Printf("error while connecting to the processor, retrying in %s: %v", backoff, err)
+		}
+
+		publish(ctx, bus, &protocols.Reponse{E: "DISCONNECTED"})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxReconnectDelay)
+	}
 }
 
-func poll(client *SpotifyClient, grpc protocols.SpotifyClient) {
-	stream, err := grpc.OnListen(context.Background(), &protocols.Request{ID: fmt.Sprintf("%d", os.Getpid())})
+// poll publishes the events of the stream until it breaks, it reports whether
+// the stream was ever connected
+func poll(ctx context.Context, client *SpotifyClient, grpc protocols.SpotifyClient, bus bus.EventBus) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := grpc.OnListen(ctx, &protocols.Request{ID: gatewayID()})
 	if err != nil {
-		return
+		return false, err
+	}
+	// the track may have changed while disconnected
+	state, err := grpc.GetTrack(ctx, &protocols.Request{ID: gatewayID()})
+	if err != nil {
+		return false, err
 	}
+	publish(ctx, bus, &protocols.Reponse{E: "CONNECTED", Track: state.GetTrack(), Player: state.GetPlayer()})
+	// a restarted processor lost the listeners
+	go report(ctx, grpc, client.Listeners)
+
 	for {
 		res, err := stream.Recv()
 		if err != nil {
-			if err == io.EOF {
-				break
+			return true, err
+		}
+		publish(ctx, bus, res)
+	}
+}
+
+func publish(ctx context.Context, bus bus.EventBus, res *protocols.Reponse) {
+	data, err := proto.Marshal(res)
+	if err == nil {
+		err = bus.Publish(ctx, TopicEvents, data)
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("error while publishing event: %v", err)
+	}
+}
+
+// handle updates the state with the event and broadcasts it to the clients
+func handle(client *SpotifyClient, res *protocols.Reponse) {
+	// connectivity events come from the leader, not the processor
+	switch res.E {
+	case "CONNECTED":
+		if res.Track != nil {
+			client.Socket.SetState(eventState(res))
+		}
+		client.setConnected(true)
+		return
+	case "DISCONNECTED":
+		client.setConnected(false)
+		return
+	}
+
+	if res.Track != nil {
+		oldState := client.Socket.GetState()
+		newState := eventState(res)
+		if oldState == nil || oldState.Track == nil {
+			// started without the processor
+			client.Socket.SetState(newState)
+		} else if res.E == "ARTISTS" {
+			// enrichments of a previous track are dropped
+			if oldState.Track.ID != newState.Track.ID {
+				return
 			}
-			log.Fatalf("error while reading stream: %v", err)
+			// the enrichment comes without the player
+			client.Socket.SetState(&State{Track: newState.Track, Player: oldState.Player})
+		} else if oldState.Track.ID != newState.Track.ID || oldState.Track.IsPlaying != newState.Track.IsPlaying || !newState.Player.SameDevice(oldState.Player) {
+			client.Socket.SetState(newState)
 		}
+	}
+
+	if msg := Dispatch(res); msg != nil {
+		// a span per broadcast, linked to the poll it comes from
+		_, span := tracing.Tracer.Start(context.Background(), "broadcast "+msg.T,
+			trace.WithLinks(tracing.Link(res.Trace)...),
+			trace.WithAttributes(attribute.Int("socket.listeners", client.Socket.Listeners())),
+		)
+		client.Socket.Broadcast(msg)
+		span.End()
+	}
+}
+
+// Dispatch converts an event of the processor stream to the dispatch
+// websocket clients receive, unknown events are nil
+func Dispatch(res *protocols.Reponse) *socket.Message {
+	switch res.E {
+	case "CHANGE":
+		return socket.Dispatch("TRACK_CHANGE", eventState(res))
+	case "DEVICE":
+		return socket.Dispatch("DEVICE_CHANGE", FromProtoToPlayer(res.Player))
+	case "ARTISTS":
+		return socket.Dispatch("ARTIST_ENRICHED", FromProtoToTrack(res.Track))
+	case "PLAYING":
+		return socket.Dispatch("PLAYBACK_CHANGE", eventState(res))
+	case "PROGRESS":
+		return socket.Dispatch("TRACK_PROGRESS", res.Progress)
+	case "QUEUE":
+		return socket.Dispatch("QUEUE_UPDATE", FromProtoToQueue(res.Queue))
+	}
+	return nil
+}
+
+// eventState returns the track of an event along with its player
+func eventState(res *protocols.Reponse) *State {
+	return &State{Track: FromProtoToTrack(res.Track), Player: FromProtoToPlayer(res.Player)}
+}
+
+// announce publishes the listeners of this process, again every heartbeat so
+// new processes learn them
+func announce(bus bus.EventBus, listeners <-chan int) {
+	ticker := time.NewTicker(listenersHeartbeat)
+	defer ticker.Stop()
 
-		if res.E == "CHANGE" {
-			client.Socket.Broadcast(socket.Dispatch("TRACK_CHANGE", FromProtoToTrack(res.Track)))
+	n := 0
+	for {
+		data, err := proto.Marshal(&protocols.Demand{ID: gatewayID(), Listeners: int64(n)})
+		if err == nil {
+			err = bus.Publish(context.Background(), TopicListeners, data)
+		}
+		if err != nil {
+			log.Printf("error while announcing listeners: %v", err)
 		}
 
-		if res.E == "PROGRESS" {
-			client.Socket.Broadcast(socket.Dispatch("TRACK_PROGRESS", res.Progress))
+		select {
+		case n = <-listeners:
+		case <-ticker.C:
 		}
+	}
+}
 
-		if res.Track != nil {
-			oldTrack := client.Socket.GetState()
-			newTrack := FromProtoToTrack(res.Track)
-			if oldTrack.ID != newTrack.ID {
-				client.Socket.SetState(newTrack)
+// report tells the processor how many listeners the whole cluster has while
+// this process leads, so it can pause polling while nobody is listening
+func report(ctx context.Context, grpc protocols.SpotifyClient, listeners *Listeners) {
+	ticker := time.NewTicker(listenersHeartbeat)
+	defer ticker.Stop()
+
+	reported := int64(-1)
+	for {
+		if total := listeners.Total(); total != reported {
+			if _, err := grpc.SetDemand(ctx, &protocols.Demand{ID: gatewayID(), Listeners: total}); err != nil {
+				if ctx.Err() == nil {
+					log.Printf("error while reporting listeners: %v", err)
+				}
+			} else {
+				reported = total
 			}
 		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-listeners.changed:
+		case <-ticker.C:
+		}
 	}
 }
 
+// gatewayID identifies the process, replicas may share the pid
+func gatewayID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// PollRate returns the seconds between polls, DefaultPollRate while Spotify answers
+func (client *SpotifyClient) PollRate() time.Duration {
+	return DefaultPollRate + time.Duration(client.pollRate.Load())
+}
+
+// ResetPollRate goes back to DefaultPollRate once Spotify answers again
+func (client *SpotifyClient) ResetPollRate() {
+	client.pollRate.Store(0)
+}
+
+// OnError slows the polls down by a second, up to 4 seconds over DefaultPollRate
 func (client *SpotifyClient) OnError() {
-	if client.PollRate > (DefaultPollRate + 3) {
-		client.PollRate = (DefaultPollRate + 3)
-	} else {
-		client.PollRate += 1
+	for {
+		backoff := client.pollRate.Load()
+		if client.pollRate.CompareAndSwap(backoff, min(backoff+1, 4)) {
+			return
+		}
 	}
 }
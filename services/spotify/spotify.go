@@ -2,24 +2,82 @@ package spotify
 
 import (
 	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"spotify/services/socket"
 
+	"github.com/goccy/go-json"
 	"github.com/knadh/koanf/v2"
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
 	"golang.org/x/oauth2"
 )
 
-const DefaultPollRate time.Duration = 5
+const (
+	DefaultPollRate time.Duration = 5
+
+	// Spotify returns up to 50 recently played tracks per page, 20 by default
+	MaxRecentlyPlayed     = 50
+	DefaultRecentlyPlayed = 20
+
+	// Spotify Web API base URL, used for the requests the library can't decode
+	BaseURL = "https://api.spotify.com/v1/"
+	// Wait before retrying a rate limited request without Retry-After
+	defaultRetryAfter = 5 * time.Second
+)
 
 type SpotifyClient struct {
-	Socket *socket.Socket[Track]
-	Client *spotify.Client
+	Socket *socket.Socket[State]
+	// Listeners of every process sharing the bus
+	Listeners *Listeners
+	// connectivity to the processor
+	upstream *upstream
+	Client   *spotify.Client
 
-	PollRate    time.Duration
+	// seconds added to DefaultPollRate while Spotify fails
+	pollRate    atomic.Int64
 	isConnected bool
+	http        *http.Client
+	// scopes granted to the refresh token
+	scopes []string
+
+	// names of the played contexts
+	contextNames *contextNameCache
+	// enriched artists
+	artists *artistCache
+	// pages of the top items and the library
+	library *libraryCache
+
+	// FallbackImageURL is used for items without artwork (local files, ads...)
+	FallbackImageURL string
+}
+
+// playerTrack is a track with the local flag, which the library doesn't decode
+type playerTrack struct {
+	spotify.FullTrack
+	IsLocal bool `json:"is_local"`
+}
+
+// playerQueue is the queue with the items left undecoded, the library decodes
+// episodes as tracks
+type playerQueue struct {
+	CurrentlyPlaying json.RawMessage   `json:"currently_playing"`
+	Queue            []json.RawMessage `json:"queue"`
+}
+
+// playerState is the player state with the item left undecoded, the library
+// decodes every item as a track and drops the currently playing type
+type playerState struct {
+	spotify.PlayerState
+	CurrentlyPlayingType string          `json:"currently_playing_type"`
+	Item                 json.RawMessage `json:"item"`
 }
 
 func New(k *koanf.Koanf) *SpotifyClient {
@@ -32,11 +90,35 @@ func New(k *koanf.Koanf) *SpotifyClient {
 	if err != nil {
 		panic(err)
 	}
+	cacheSize, cacheTTL := DefaultArtistCacheSize, DefaultArtistCacheTTL
+	if k.Exists("spotify.artists.cache_size") {
+		cacheSize = k.Int("spotify.artists.cache_size")
+	}
+	if k.Exists("spotify.artists.cache_ttl") {
+		cacheTTL = k.Duration("spotify.artists.cache_ttl")
+	}
+	libraryTTL := DefaultLibraryCacheTTL
+	if k.Exists("spotify.library.cache_ttl") {
+		libraryTTL = k.Duration("spotify.library.cache_ttl")
+	}
+
+	scope, _ := token.Extra("scope").(string)
+	httpClient := auth.Client(context.Background(), token)
+	// spans of the requests continue the trace of their context, which isn't
+	// sent to Spotify
+	httpClient.Transport = otelhttp.NewTransport(countedTransport{next: httpClient.Transport}, otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()))
 	return &SpotifyClient{
-		Client:      spotify.New(auth.Client(context.Background(), token), spotify.WithRetry(true)),
+		Client:      spotify.New(httpClient, spotify.WithRetry(true)),
 		isConnected: len(token.AccessToken) > 0,
-		PollRate:    DefaultPollRate,
 		Socket:      nil,
+		Listeners:   nil,
+		http:        httpClient,
+		scopes:      strings.Fields(scope),
+
+		FallbackImageURL: k.String("spotify.fallback_image_url"),
+		artists:          newArtistCache(cacheSize, cacheTTL, k.String("spotify.artists.cache_file")),
+		library:          newLibraryCache(libraryTTL),
+		contextNames:     newContextNameCache(contextNamesSize),
 	}
 }
 
@@ -44,90 +126,300 @@ func (sc *SpotifyClient) IsConnected() bool {
 	return sc.isConnected
 }
 
-func (c *SpotifyClient) GetSpotifyStatus() (*Track, error) {
-	if now, err := c.GetNowPlaying(false); err != nil {
+// HasScope reports whether the refresh token was granted the scope
+func (sc *SpotifyClient) HasScope(scope string) bool {
+	return slices.Contains(sc.scopes, scope)
+}
+
+func (c *SpotifyClient) GetSpotifyStatus(ctx context.Context) (*State, error) {
+	if now, err := c.GetNowPlaying(ctx, false); err != nil {
 		return nil, err
 	} else {
 		if now != nil {
-			return now.(*Track), nil
+			return now.(*State), nil
 		}
 	}
 
-	last, err := c.GetLastPlayed(false, 1)
+	last, err := c.GetLastPlayed(ctx, false, &RecentlyPlayedOptions{Limit: 1})
 	if err != nil {
 		return nil, err
 	}
-	return last.([]*Track)[0], nil
+	if tracks := last.([]*Track); len(tracks) > 0 {
+		return &State{Track: tracks[0]}, nil
+	}
+	// nothing playing nor played yet
+	return &State{Track: &Track{Type: UnknownItem, Artists: []Artist{}, Album: &Album{ImageURL: c.FallbackImageURL}}}, nil
 }
 
-func (c *SpotifyClient) GetNowPlaying(raw bool) (any, error) {
-	if now, err := c.Client.PlayerState(context.Background()); err != nil {
+func (c *SpotifyClient) GetNowPlaying(ctx context.Context, raw bool) (any, error) {
+	if raw {
+		return c.Client.PlayerCurrentlyPlaying(ctx, spotify.AdditionalTypes(spotify.EpisodeAdditionalType))
+	}
+
+	now, err := c.getPlayerState(ctx)
+	if err != nil || now == nil {
 		return nil, err
-	} else {
-		if !raw {
-			if now != nil && now.Item != nil {
-				var artists []Artist
-				for _, artist := range now.Item.Artists {
-					artists = append(artists, Artist{Name: artist.Name, URL: artist.ExternalURLs["spotify"]})
-				}
-				return &Track{
-					ID:        now.Item.ID,
-					Title:     now.Item.Name,
-					URL:       now.Item.ExternalURLs["spotify"],
-					IsPlaying: now.Playing,
-					Timestamp: &Timestamp{
-						Progress: now.Progress,
-						Duration: now.Item.Duration,
-					},
-					Artists: artists,
-					Album: &Album{
-						ID:       now.Item.Album.ID,
-						ImageURL: now.Item.Album.Images[0].URL,
-						Name:     now.Item.Album.Name,
-						URL:      now.Item.Album.ExternalURLs["spotify"],
-					},
-				}, nil
-			}
-			return nil, nil
-		} else {
-			rawData, err := c.Client.PlayerCurrentlyPlaying(context.Background())
-			return rawData, err
+	}
+
+	var track *Track
+	timestamp := &Timestamp{Progress: now.Progress}
+	switch {
+	case now.CurrentlyPlayingType == AdItem:
+		// ads come without item, only the progress is known
+		track = &Track{
+			Title:   "Advertisement",
+			Type:    AdItem,
+			Artists: []Artist{},
+			Album:   &Album{ImageURL: c.FallbackImageURL},
+		}
+	case now.Item == nil || string(now.Item) == "null":
+		return nil, nil
+	default:
+		if track, err = c.newItem(now.CurrentlyPlayingType, now.Item); err != nil {
+			return nil, err
 		}
+		timestamp.Duration = track.Duration
 	}
+
+	track.IsPlaying = now.Playing
+	track.Timestamp = timestamp
+	return &State{Track: track, Player: c.newPlayer(ctx, &now.PlayerState)}, nil
 }
 
-func (c *SpotifyClient) GetLastPlayed(raw bool, limit int) (any, error) {
-	if last, err := c.Client.PlayerRecentlyPlayed(context.Background()); err != nil {
+// newItem converts a track or an episode, items of other types are converted
+// as tracks of unknown type
+func (c *SpotifyClient) newItem(itemType string, raw json.RawMessage) (*Track, error) {
+	if itemType == EpisodeItem {
+		var episode spotify.EpisodePage
+		if err := json.Unmarshal(raw, &episode); err != nil {
+			return nil, err
+		}
+		return &Track{
+			ID:         episode.ID,
+			Title:      episode.Name,
+			Type:       EpisodeItem,
+			URL:        episode.ExternalURLs["spotify"],
+			Artists:    []Artist{},
+			Duration:   episode.Duration_ms,
+			Explicit:   episode.Explicit,
+			PreviewURL: episode.AudioPreviewURL,
+			Show: &Show{
+				ID:        episode.Show.ID,
+				ImageURL:  c.imageURL(episode.Show.Images),
+				Name:      episode.Show.Name,
+				Publisher: episode.Show.Publisher,
+				URL:       episode.Show.ExternalURLs["spotify"],
+			},
+		}, nil
+	}
+
+	var item playerTrack
+	if err := json.Unmarshal(raw, &item); err != nil {
 		return nil, err
-	} else {
-		if limit > len(last) || limit < 1 {
-			limit = len(last)
+	}
+	track := c.newTrack(&item.FullTrack, item.IsLocal)
+	if itemType != TrackItem {
+		track.Type = UnknownItem
+	}
+	return track, nil
+}
+
+func (c *SpotifyClient) newPlayer(ctx context.Context, state *spotify.PlayerState) *Player {
+	player := &Player{
+		Device: &Device{
+			ID:     state.Device.ID,
+			Name:   state.Device.Name,
+			Type:   state.Device.Type,
+			Volume: state.Device.Volume,
+		},
+		Repeat:  state.RepeatState,
+		Shuffle: state.ShuffleState,
+	}
+	if state.PlaybackContext.URI != "" {
+		player.Context = &PlaybackContext{
+			Name: c.contextName(ctx, state.PlaybackContext),
+			Type: state.PlaybackContext.Type,
+			URI:  state.PlaybackContext.URI,
+			URL:  state.PlaybackContext.ExternalURLs["spotify"],
+		}
+	}
+	return player
+}
+
+// newTrack converts a spotify track
+func (c *SpotifyClient) newTrack(item *spotify.FullTrack, isLocal bool) *Track {
+	artists := []Artist{}
+	for _, artist := range item.Artists {
+		artists = append(artists, Artist{ID: artist.ID, Name: artist.Name, URL: artist.ExternalURLs["spotify"]})
+	}
+	// enrichment is only requested on track change, meanwhile use what is known
+	c.artists.Fill(artists)
+
+	id := item.ID
+	if isLocal {
+		// local files have no ID, the URI is unique enough to detect changes
+		id = spotify.ID(item.URI)
+	}
+	return &Track{
+		ID:          id,
+		Title:       item.Name,
+		Type:        TrackItem,
+		URL:         item.ExternalURLs["spotify"],
+		IsLocal:     isLocal,
+		Artists:     artists,
+		DiscNumber:  item.DiscNumber,
+		Duration:    item.Duration,
+		Explicit:    item.Explicit,
+		ISRC:        item.ExternalIDs["isrc"],
+		Popularity:  item.Popularity,
+		PreviewURL:  item.PreviewURL,
+		TrackNumber: item.TrackNumber,
+		Album: &Album{
+			ID:          item.Album.ID,
+			ImageURL:    c.imageURL(item.Album.Images),
+			Name:        item.Album.Name,
+			ReleaseDate: item.Album.ReleaseDate,
+			URL:         item.Album.ExternalURLs["spotify"],
+		},
+	}
+}
+
+// fullTrack wraps a simple track, full tracks shadow its album and external IDs
+func fullTrack(track spotify.SimpleTrack) *spotify.FullTrack {
+	return &spotify.FullTrack{
+		SimpleTrack: track,
+		Album:       track.Album,
+		ExternalIDs: map[string]string{"isrc": track.ExternalIDs.ISRC},
+	}
+}
+
+// imageURL returns the first (widest) image or the configured fallback
+func (c *SpotifyClient) imageURL(images []spotify.Image) string {
+	if len(images) == 0 {
+		return c.FallbackImageURL
+	}
+	return images[0].URL
+}
+
+// getPlayerState requests the player state including episodes
+func (c *SpotifyClient) getPlayerState(ctx context.Context) (*playerState, error) {
+	var state playerState
+	if found, err := c.get(ctx, "me/player?additional_types="+spotify.EpisodeAdditionalType, &state); err != nil || !found {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// get requests and decodes an endpoint of the Web API, reporting whether it had
+// content. Like the client WithRetry, rate limited requests are retried after
+// Retry-After; other failures are spotify.Error keeping the status.
+func (c *SpotifyClient) get(ctx context.Context, path string, v any) (bool, error) {
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, BaseURL+path, nil)
+		if err != nil {
+			return false, err
 		}
 
+		res, err := c.http.Do(req)
+		if err != nil {
+			return false, err
+		}
+		if res.StatusCode != http.StatusTooManyRequests {
+			defer res.Body.Close()
+			return decodeResponse(res, v)
+		}
+		res.Body.Close()
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(retryAfter(res)):
+		}
+	}
+}
+
+// decodeResponse decodes the body of a Web API response into v, reporting
+// whether it had content
+func decodeResponse(res *http.Response, v any) (bool, error) {
+	switch res.StatusCode {
+	case http.StatusNoContent:
+		return false, nil
+	case http.StatusOK:
+		return true, json.NewDecoder(res.Body).Decode(v)
+	}
+
+	var body struct {
+		Error spotify.Error `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Error.Message == "" {
+		body.Error.Message = "spotify: unexpected status " + res.Status
+	}
+	body.Error.Status = res.StatusCode
+	return false, body.Error
+}
+
+// retryAfter returns the wait Spotify asks for before retrying, in seconds
+func retryAfter(res *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultRetryAfter
+}
+
+// GetLastPlayed returns a page of recently played tracks, opts may be nil
+func (c *SpotifyClient) GetLastPlayed(ctx context.Context, raw bool, opts *RecentlyPlayedOptions) (any, error) {
+	if opts == nil {
+		opts = &RecentlyPlayedOptions{}
+	}
+	if opts.Limit > MaxRecentlyPlayed {
+		opts.Limit = MaxRecentlyPlayed
+	} else if opts.Limit < 0 {
+		opts.Limit = 0
+	}
+
+	if last, err := c.Client.PlayerRecentlyPlayedOpt(ctx, opts); err != nil {
+		return nil, err
+	} else {
 		if raw {
-			return last[:limit], nil
-		}
-		var tracks []*Track
-		for i := range limit {
-			var artists []Artist
-			for _, artist := range last[i].Track.Artists {
-				artists = append(artists, Artist{Name: artist.Name, URL: artist.ExternalURLs["spotify"]})
-			}
-			tracks = append(tracks, &Track{
-				ID:        last[i].Track.ID,
-				Title:     last[i].Track.Name,
-				URL:       last[i].Track.ExternalURLs["spotify"],
-				IsPlaying: false,
-				PlayedAt:  &last[i].PlayedAt,
-				Artists:   artists,
-				Album: &Album{
-					ID:       last[i].Track.Album.ID,
-					ImageURL: last[i].Track.Album.Images[0].URL,
-					Name:     last[i].Track.Album.Name,
-					URL:      last[i].Track.Album.ExternalURLs["spotify"],
-				},
-			})
+			return last, nil
+		}
+		tracks := []*Track{}
+		for i := range last {
+			track := c.newTrack(fullTrack(last[i].Track), false)
+			track.PlayedAt = &last[i].PlayedAt
+			tracks = append(tracks, track)
 		}
 		return tracks, nil
 	}
 }
+
+// GetQueue returns the item playing and the ones up next
+func (c *SpotifyClient) GetQueue(ctx context.Context) (*Queue, error) {
+	var raw playerQueue
+	if _, err := c.get(ctx, "me/player/queue", &raw); err != nil {
+		return nil, err
+	}
+
+	queue := &Queue{Items: []*Track{}}
+	for i, item := range append([]json.RawMessage{raw.CurrentlyPlaying}, raw.Queue...) {
+		if item == nil || string(item) == "null" {
+			continue
+		}
+		var kind struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(item, &kind); err != nil {
+			return nil, err
+		}
+		track, err := c.newItem(kind.Type, item)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			queue.Current = track
+		} else {
+			queue.Items = append(queue.Items, track)
+		}
+	}
+	return queue, nil
+}
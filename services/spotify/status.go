@@ -0,0 +1,65 @@
+package spotify
+
+import (
+	"sync"
+	"time"
+
+	"spotify/services/socket"
+)
+
+// ServiceStatus is the connectivity of the gateway to the processor
+type ServiceStatus struct {
+	// Whether the processor stream is connected
+	Connected bool `json:"connected"`
+	// When the connectivity last changed
+	Since time.Time `json:"since"`
+}
+
+type upstream struct {
+	mu     sync.Mutex
+	status ServiceStatus
+}
+
+func newUpstream(connected bool) *upstream {
+	return &upstream{status: ServiceStatus{Connected: connected, Since: time.Now()}}
+}
+
+// set updates the connectivity, it reports whether it changed
+func (u *upstream) set(connected bool) (ServiceStatus, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.status.Connected == connected {
+		return u.status, false
+	}
+	u.status = ServiceStatus{Connected: connected, Since: time.Now()}
+	return u.status, true
+}
+
+func (u *upstream) get() ServiceStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.status
+}
+
+// Status returns the connectivity to the processor
+func (client *SpotifyClient) Status() ServiceStatus {
+	return client.upstream.get()
+}
+
+// setConnected flags the state as stale while disconnected, and tells the
+// websocket clients whenever the connectivity changes
+func (client *SpotifyClient) setConnected(connected bool) {
+	status, changed := client.upstream.set(connected)
+	if !changed {
+		return
+	}
+
+	if !connected {
+		if state := client.Socket.GetState(); state != nil && state.Track != nil {
+			stale := *state.Track
+			stale.Stale = true
+			client.Socket.SetState(&State{Track: &stale, Player: state.Player})
+		}
+	}
+	client.Socket.Broadcast(socket.Dispatch("SERVICE_STATUS", status))
+}
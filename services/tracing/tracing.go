@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"log"
+
+	"github.com/knadh/koanf/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+// Name of the tracer of every package
+const Name = "spotify"
+
+// Tracer starts the spans of the services
+var Tracer = otel.Tracer(Name)
+
+// Setup exports the spans of the binary to the OTLP collector at
+// tracing.endpoint, named after tracing.service_name or name. Without endpoint
+// no span is recorded, but the trace context still goes through.
+func Setup(name string) func(lc fx.Lifecycle, k *koanf.Koanf) error {
+	return func(lc fx.Lifecycle, k *koanf.Koanf) error {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+		endpoint := k.String("tracing.endpoint")
+		if endpoint == "" {
+			return nil
+		}
+		if service := k.String("tracing.service_name"); service != "" {
+			name = service
+		}
+
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if k.Bool("tracing.insecure") {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		// the exporter connects lazily, a collector down only drops spans
+		exporter, err := otlptracegrpc.New(context.Background(), opts...)
+		if err != nil {
+			return err
+		}
+		res, err := resource.New(context.Background(),
+			resource.WithAttributes(semconv.ServiceName(name)),
+			resource.WithFromEnv(),
+			resource.WithTelemetrySDK(),
+		)
+		if err != nil {
+			return err
+		}
+
+		ratio := 1.0
+		if k.Exists("tracing.sample_ratio") {
+			ratio = k.Float64("tracing.sample_ratio")
+		}
+		provider := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		)
+		otel.SetTracerProvider(provider)
+		log.Printf("Exporting traces of %s to \"%s\"", name, endpoint)
+
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				// flushes the spans left
+				return provider.Shutdown(ctx)
+			},
+		})
+		return nil
+	}
+}
+
+// Carrier returns the trace context of ctx, to be carried along a message
+func Carrier(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Link links a span to the trace context of a carrier, nothing without one
+func Link(carrier map[string]string) []trace.Link {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(carrier))
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return []trace.Link{{SpanContext: sc}}
+	}
+	return nil
+}
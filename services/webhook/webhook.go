@@ -0,0 +1,464 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	proto "spotify/protocols"
+	"spotify/services/socket"
+
+	"github.com/goccy/go-json"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+	DefaultTimeout     = 10 * time.Second
+
+	// Deliveries waiting per endpoint, newer ones are dead-lettered once full
+	queueSize = 256
+	// Deliveries kept per endpoint for the status
+	recentSize = 20
+
+	// Headers of every delivery
+	HeaderEvent     = "X-Spotify-Event"
+	HeaderDelivery  = "X-Spotify-Delivery"
+	HeaderSignature = "X-Spotify-Signature-256"
+)
+
+// Delivery states
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Endpoint is a configured receiver of the events
+type Endpoint struct {
+	// URL the events are posted to
+	URL string `json:"url"`
+	// Events sent, every event but TRACK_PROGRESS when empty
+	Events []string `json:"events"`
+	// secret the payloads are signed with
+	secret string
+}
+
+// Accepts reports whether the endpoint wants the event
+func (e *Endpoint) Accepts(event string) bool {
+	if len(e.Events) == 0 {
+		// progress fires every few seconds, only sent on demand
+		return event != "TRACK_PROGRESS"
+	}
+	return slices.Contains(e.Events, event)
+}
+
+// Delivery is an event sent (or being sent) to an endpoint
+type Delivery struct {
+	// Random ID, sent in the X-Spotify-Delivery header
+	ID string `json:"id"`
+	// Event dispatched
+	Event string `json:"event"`
+	// URL of the endpoint
+	URL string `json:"url"`
+	// Attempts made so far
+	Attempts int `json:"attempts"`
+	// "pending", "delivered" or "failed"
+	Status string `json:"status"`
+	// HTTP status of the last attempt, if any
+	StatusCode int `json:"status_code,omitempty"`
+	// Error of the last attempt
+	Error string `json:"error,omitempty"`
+	// When the event was dispatched
+	CreatedAt time.Time `json:"created_at"`
+	// When the last attempt ended
+	UpdatedAt time.Time `json:"updated_at"`
+
+	payload []byte
+}
+
+// EndpointStatus summarizes the deliveries of an endpoint
+type EndpointStatus struct {
+	Endpoint
+	// Deliveries waiting to be sent
+	Pending int `json:"pending"`
+	// Deliveries sent since start
+	Delivered int64 `json:"delivered"`
+	// Deliveries dead-lettered since start
+	Failed int64 `json:"failed"`
+	// Latest deliveries, newest first
+	Recent []Delivery `json:"recent"`
+}
+
+type endpoint struct {
+	Endpoint
+	queue chan *Delivery
+	// ctx is done once the endpoint is removed
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	delivered int64
+	failed    int64
+	recent    []*Delivery
+}
+
+// Webhooks posts the socket dispatches to the configured endpoints. Every
+// endpoint has its own queue, so a slow endpoint doesn't hold the others and
+// events arrive in order.
+type Webhooks struct {
+	endpoints []*endpoint
+	mu        sync.RWMutex
+	client    *http.Client
+
+	// MaxAttempts before a delivery is dead-lettered
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled on each retry
+	Backoff time.Duration
+	// DeadLetter is the file the failed deliveries are appended to, as JSON lines
+	DeadLetter string
+
+	deadMu sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New(k *koanf.Koanf) *Webhooks {
+	maxAttempts, backoff, timeout := DefaultMaxAttempts, DefaultBackoff, DefaultTimeout
+	if k.Exists("webhook.max_attempts") {
+		maxAttempts = max(k.Int("webhook.max_attempts"), 1)
+	}
+	if k.Exists("webhook.backoff") {
+		backoff = k.Duration("webhook.backoff")
+	}
+	if k.Exists("webhook.timeout") {
+		timeout = k.Duration("webhook.timeout")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhooks{
+		client:      &http.Client{Timeout: timeout},
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		DeadLetter:  k.String("webhook.dead_letter"),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	w.SetEndpoints(k)
+	return w
+}
+
+// SetEndpoints replaces the endpoints with the ones of webhook.endpoints. The
+// endpoints whose URL remains keep their queue and deliveries, the removed
+// ones drop theirs.
+func (w *Webhooks) SetEndpoints(k *koanf.Koanf) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := make(map[string]*endpoint, len(w.endpoints))
+	for _, e := range w.endpoints {
+		current[e.URL] = e
+	}
+
+	endpoints := []*endpoint{}
+	for _, conf := range k.Slices("webhook.endpoints") {
+		url := conf.String("url")
+		if e, ok := current[url]; ok {
+			delete(current, url)
+			e.mu.Lock()
+			e.Events, e.secret = conf.Strings("events"), conf.String("secret")
+			e.mu.Unlock()
+			endpoints = append(endpoints, e)
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(w.ctx)
+		e := &endpoint{
+			Endpoint: Endpoint{URL: url, Events: conf.Strings("events"), secret: conf.String("secret")},
+			queue:    make(chan *Delivery, queueSize),
+			ctx:      ctx,
+			cancel:   cancel,
+		}
+		endpoints = append(endpoints, e)
+		go w.run(e)
+	}
+
+	for _, e := range current {
+		e.cancel()
+	}
+	w.endpoints = endpoints
+}
+
+// Send queues the dispatch for every endpoint accepting its event
+func (w *Webhooks) Send(msg *socket.Message) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var payload []byte
+	for _, e := range w.endpoints {
+		e.mu.Lock()
+		accepts := e.Accepts(msg.T)
+		e.mu.Unlock()
+		if !accepts {
+			continue
+		}
+		if payload == nil {
+			// the same JSON websocket clients receive
+			if payload = msg.ToBytes(); payload == nil {
+				return
+			}
+		}
+
+		now := time.Now()
+		delivery := &Delivery{
+			ID:        newID(),
+			Event:     msg.T,
+			URL:       e.URL,
+			Status:    StatusPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+			payload:   payload,
+		}
+		select {
+		case e.queue <- delivery:
+		default:
+			delivery.Error = "queue full"
+			w.fail(e, delivery)
+		}
+	}
+}
+
+// Len returns the amount of endpoints
+func (w *Webhooks) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.endpoints)
+}
+
+// Status reports the deliveries of every endpoint
+func (w *Webhooks) Status() []EndpointStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	status := make([]EndpointStatus, len(w.endpoints))
+	for i, e := range w.endpoints {
+		e.mu.Lock()
+		recent := make([]Delivery, len(e.recent))
+		for j, delivery := range e.recent {
+			recent[len(e.recent)-1-j] = *delivery
+		}
+		status[i] = EndpointStatus{
+			Endpoint:  e.Endpoint,
+			Pending:   len(e.queue),
+			Delivered: e.delivered,
+			Failed:    e.failed,
+			Recent:    recent,
+		}
+		e.mu.Unlock()
+	}
+	return status
+}
+
+// Close stops delivering, the deliveries still queued are dropped
+func (w *Webhooks) Close() {
+	w.cancel()
+}
+
+func (w *Webhooks) run(e *endpoint) {
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case delivery := <-e.queue:
+			w.deliver(e, delivery)
+		}
+	}
+}
+
+// deliver posts the delivery until it succeeds, retrying with exponential backoff
+func (w *Webhooks) deliver(e *endpoint, delivery *Delivery) {
+	e.mu.Lock()
+	e.recent = append(e.recent, delivery)
+	if len(e.recent) > recentSize {
+		e.recent = e.recent[1:]
+	}
+	e.mu.Unlock()
+
+	backoff := w.Backoff
+	for {
+		code, err := w.post(e, delivery)
+
+		e.mu.Lock()
+		delivery.Attempts++
+		delivery.StatusCode = code
+		delivery.UpdatedAt = time.Now()
+		if err == nil {
+			delivery.Status, delivery.Error = StatusDelivered, ""
+			e.delivered++
+			e.mu.Unlock()
+			return
+		}
+		delivery.Error = err.Error()
+		// client errors won't change on retry, but rate limits will
+		retry := delivery.Attempts < w.MaxAttempts && (code == 0 || code == http.StatusTooManyRequests || code >= 500)
+		e.mu.Unlock()
+
+		if !retry {
+			w.fail(e, delivery)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Webhooks) post(e *endpoint, delivery *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(e.ctx, http.MethodPost, e.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Spotify-Server-Webhook")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	e.mu.Lock()
+	secret := e.secret
+	e.mu.Unlock()
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, delivery.payload))
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// fail dead-letters the delivery
+func (w *Webhooks) fail(e *endpoint, delivery *Delivery) {
+	e.mu.Lock()
+	delivery.Status = StatusFailed
+	e.failed++
+	entry, err := json.Marshal(struct {
+		*Delivery
+		Payload json.RawMessage `json:"payload"`
+	}{delivery, delivery.payload})
+	e.mu.Unlock()
+	if err != nil {
+		log.Printf("error while dead-lettering webhook delivery %s: %v", delivery.ID, err)
+		return
+	}
+
+	log.Printf("webhook delivery %s of %s to %s failed: %s", delivery.ID, delivery.Event, delivery.URL, delivery.Error)
+	if w.DeadLetter == "" {
+		return
+	}
+
+	w.deadMu.Lock()
+	defer w.deadMu.Unlock()
+	file, err := os.OpenFile(w.DeadLetter, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err == nil {
+		_, err = file.Write(append(entry, '\n'))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("error while dead-lettering webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// Sign returns the signature header of the payload: "sha256=" and the
+// hex-encoded HMAC-SHA256 of the payload with the secret
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func (status *EndpointStatus) ToProto() *proto.Webhook {
+	recent := make([]*proto.Delivery, len(status.Recent))
+	for i, delivery := range status.Recent {
+		recent[i] = delivery.ToProto()
+	}
+	return &proto.Webhook{
+		Delivered: status.Delivered,
+		Events:    status.Events,
+		Failed:    status.Failed,
+		Pending:   int64(status.Pending),
+		Recent:    recent,
+		URL:       status.URL,
+	}
+}
+
+func FromProtoToEndpointStatus(pb *proto.Webhook) *EndpointStatus {
+	recent := make([]Delivery, len(pb.Recent))
+	for i, delivery := range pb.Recent {
+		recent[i] = *FromProtoToDelivery(delivery)
+	}
+	return &EndpointStatus{
+		Endpoint:  Endpoint{URL: pb.URL, Events: pb.Events},
+		Delivered: pb.Delivered,
+		Failed:    pb.Failed,
+		Pending:   int(pb.Pending),
+		Recent:    recent,
+	}
+}
+
+func (delivery *Delivery) ToProto() *proto.Delivery {
+	return &proto.Delivery{
+		Attempts:   int64(delivery.Attempts),
+		CreatedAt:  delivery.CreatedAt.UnixMilli(),
+		Error:      delivery.Error,
+		Event:      delivery.Event,
+		ID:         delivery.ID,
+		Status:     delivery.Status,
+		StatusCode: int64(delivery.StatusCode),
+		URL:        delivery.URL,
+		UpdatedAt:  delivery.UpdatedAt.UnixMilli(),
+	}
+}
+
+func FromProtoToDelivery(pb *proto.Delivery) *Delivery {
+	return &Delivery{
+		Attempts:   int(pb.Attempts),
+		CreatedAt:  time.UnixMilli(pb.CreatedAt),
+		Error:      pb.Error,
+		Event:      pb.Event,
+		ID:         pb.ID,
+		Status:     pb.Status,
+		StatusCode: int(pb.StatusCode),
+		URL:        pb.URL,
+		UpdatedAt:  time.UnixMilli(pb.UpdatedAt),
+	}
+}
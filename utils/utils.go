@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"time"
 )
 
 var valueRegex = regexp.MustCompile(`[#]\{([\w\.]+)\}`)
@@ -38,3 +40,11 @@ func Executable() (string, string, error) {
 	}
 	return dir, file, nil
 }
+
+// Parse time from RFC 3339 or unix milliseconds (time, error)
+func ParseTime(value string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}